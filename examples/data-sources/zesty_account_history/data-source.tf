@@ -0,0 +1,14 @@
+# List how the Kompass threshold of an account changed over time.
+data "zesty_account_history" "example" {
+  account_id = "123456789012"
+}
+
+output "kompass_thresholds" {
+  value = {
+    for snapshot in data.zesty_account_history.example.snapshots :
+    snapshot.timestamp => one([
+      for product in snapshot.products : lookup(product.values_map, "threshold", null)
+      if product.name == "Kompass"
+    ])
+  }
+}
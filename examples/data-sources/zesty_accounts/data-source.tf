@@ -1,2 +1,8 @@
 # List all accounts.
 data "zesty_accounts" "all" {}
+
+# List AWS accounts that have Kompass.
+data "zesty_accounts" "aws_kompass" {
+  cloud_provider = "AWS"
+  product        = "Kompass"
+}
@@ -0,0 +1,9 @@
+# Check that the Zesty API is reachable and accepts the configured token.
+data "zesty_health" "api" {}
+
+check "zesty_api" {
+  assert {
+    condition     = data.zesty_health.api.healthy
+    error_message = "The Zesty API is unhealthy."
+  }
+}
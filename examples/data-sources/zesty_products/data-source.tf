@@ -0,0 +1,2 @@
+# List the products that can be activated on an account.
+data "zesty_products" "all" {}
@@ -3,3 +3,24 @@ provider "zesty" {
   host  = "https://kompass-onboarding.zesty.co"
   token = "token"
 }
+
+# Profile-based authentication, reading host and token from the [staging]
+# section of ~/.zesty/credentials
+provider "zesty" {
+  alias   = "staging"
+  profile = "staging"
+}
+
+# Vault-based authentication, reading the token from a KV secret using the
+# VAULT_ADDR and VAULT_TOKEN environment variables
+provider "zesty" {
+  alias            = "vault"
+  vault_token_path = "secret/data/zesty"
+}
+
+# File-based authentication, reading the token from a secret mounted by a CI
+# system
+provider "zesty" {
+  alias      = "ci"
+  token_file = "/run/secrets/zesty-token"
+}
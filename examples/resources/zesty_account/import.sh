@@ -1,2 +1,11 @@
 # Order can be imported by specifying the account ID.
 terraform import zesty_account.example 123456789012
+
+# Accounts in a specific organization can be imported with org_id/cloud_provider/account_id.
+terraform import zesty_account.example 42/AWS/123456789012
+
+# Accounts of one of the tenants of the provider are imported with the name of the tenant as a prefix.
+terraform import zesty_account.example tenant_b:123456789012
+
+# To import every account of an organization, list the import IDs with the zesty_accounts data source:
+#   [for a in data.zesty_accounts.org.accounts : "42/${a.cloud_provider}/${a.id}"]
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+// BatchConcurrency bounds the number of concurrent requests issued by batch methods.
+const BatchConcurrency = 4
+
+// CreateAccounts creates every account in payloads. The API has no bulk endpoint, so the
+// accounts are created with concurrent single requests. The returned slice is index-aligned
+// with payloads; entries whose request failed are left empty and their errors are joined.
+func (c *Client) CreateAccounts(ctx context.Context, payloads []models.Payload) ([]models.Account, error) {
+	return c.batch(ctx, payloads, c.CreateAccount)
+}
+
+// UpdateAccounts updates every account in payloads, with the same semantics as CreateAccounts.
+func (c *Client) UpdateAccounts(ctx context.Context, payloads []models.Payload) ([]models.Account, error) {
+	return c.batch(ctx, payloads, c.UpdateAccount)
+}
+
+func (c *Client) batch(ctx context.Context, payloads []models.Payload, call func(context.Context, models.Payload) (*models.Account, error)) ([]models.Account, error) {
+	accounts := make([]models.Account, len(payloads))
+	errs := make([]error, len(payloads))
+
+	sem := make(chan struct{}, BatchConcurrency)
+	var wg sync.WaitGroup
+	for i, payload := range payloads {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			account, err := call(ctx, payload)
+			if err != nil {
+				errs[i] = fmt.Errorf("account %s: %w", payload.AccountID, err)
+				return
+			}
+			accounts[i] = *account
+		}()
+	}
+	wg.Wait()
+
+	return accounts, errors.Join(errs...)
+}
@@ -0,0 +1,83 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+func newBatchServer(t *testing.T, method string, failing string, inFlight, maxInFlight *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(inFlight, 1)
+		defer atomic.AddInt32(inFlight, -1)
+		for {
+			peak := atomic.LoadInt32(maxInFlight)
+			if current <= peak || atomic.CompareAndSwapInt32(maxInFlight, peak, current) {
+				break
+			}
+		}
+
+		assert.Equal(t, method, r.Method)
+		var payload models.Payload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		if payload.AccountID == failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("boom"))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(models.Account{AccountID: payload.AccountID})
+	}))
+}
+
+func TestClient_CreateAccounts(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := newBatchServer(t, http.MethodPost, "", &inFlight, &maxInFlight)
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	payloads := make([]models.Payload, 10)
+	for i := range payloads {
+		payloads[i] = models.Payload{AccountID: string(rune('a' + i))}
+	}
+
+	accounts, err := c.CreateAccounts(context.Background(), payloads)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, len(payloads))
+	for i, account := range accounts {
+		assert.Equal(t, payloads[i].AccountID, account.AccountID)
+	}
+	assert.LessOrEqual(t, int(maxInFlight), client.BatchConcurrency)
+}
+
+func TestClient_UpdateAccounts_PartialFailure(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := newBatchServer(t, http.MethodPut, "acc2", &inFlight, &maxInFlight)
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	payloads := []models.Payload{{AccountID: "acc1"}, {AccountID: "acc2"}, {AccountID: "acc3"}}
+	accounts, err := c.UpdateAccounts(context.Background(), payloads)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "account acc2: PUT /account: status: 500, body: boom")
+	assert.NotContains(t, err.Error(), "acc1")
+	assert.NotContains(t, err.Error(), "acc3")
+
+	var apiErr *client.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+
+	assert.Equal(t, []models.Account{{AccountID: "acc1"}, {}, {AccountID: "acc3"}}, accounts)
+}
@@ -2,176 +2,929 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+	"golang.org/x/time/rate"
 )
 
+// DefaultTimeout is the HTTP client timeout used unless configured otherwise. It is the timeout the
+// client always had, as large onboarding calls can take minutes.
+const DefaultTimeout = 180 * time.Second
+
+// DefaultValidateAttempts and DefaultValidateBackoff control how Validate retries transient failures.
+const (
+	DefaultValidateAttempts = 3
+	DefaultValidateBackoff  = time.Second
+)
+
+// DefaultDeletePollInterval is the wait before WaitForAccountDeleted checks again whether an account
+// is gone unless configured otherwise.
+const DefaultDeletePollInterval = time.Second
+
+// DefaultMaxResponseBytes is the largest response body DoRequest reads unless configured otherwise.
+const DefaultMaxResponseBytes = 10 << 20
+
+// UserAgentPrefix identifies the provider in the User-Agent header of every request.
+const UserAgentPrefix = "terraform-provider-zesty"
+
 type Client struct {
-	HostURL    string
+	HostURL string
+	// BasePath is prepended to the path of every endpoint, for APIs served under a prefix by a
+	// gateway. It is normalized by NormalizeBasePath, and empty when the API is served at HostURL.
+	BasePath   string
 	HTTPClient *http.Client
 	Token      string
+	UserAgent  string
+	// Limiter throttles requests to the API. NewClient sets an unlimited limiter.
+	Limiter *rate.Limiter
+	// ValidateAttempts is the number of times Validate tries the API before giving up on
+	// transient failures, waiting about ValidateBackoff before the first retry and doubling it after
+	// each. Waits are jittered down to half their length.
+	ValidateAttempts int
+	ValidateBackoff  time.Duration
+	// DeletePollInterval is the wait before WaitForAccountDeleted checks again whether an account is
+	// gone, doubled after each check as in PollUntil.
+	DeletePollInterval time.Duration
+	// RetryBudget caps the retries of all requests sent with the client. Nil means unlimited.
+	RetryBudget *RetryBudget
+	// Retry configures how DoRequest retries failed requests. The zero value disables retries.
+	Retry RetryConfig
+	// MaxResponseBytes bounds the size of response bodies read by DoRequest. Zero or less disables the limit.
+	MaxResponseBytes int64
+	// ReadOnly turns CreateAccount, UpdateAccount and DeleteAccount into no-ops that send nothing to
+	// the API. Create and update return the payload echoed back as an account.
+	ReadOnly bool
+	// ExtraHeaders are sent with every request, for example a tenant header required by a gateway
+	// in front of the API. ReservedHeaders are never overridden.
+	ExtraHeaders map[string]string
+	// AcceptableStatusCodes lists the status codes DoRequest treats as successful, for gateways that
+	// rewrite the status codes of the API. Empty means any 2xx status code.
+	AcceptableStatusCodes []int
+	// LogLevel is the level of the LogSubsystem logger DoRequest logs requests and responses to, so
+	// their verbosity can be raised without raising the level of every provider. hclog.NoLevel, the
+	// zero value, inherits the level of the provider logger.
+	LogLevel hclog.Level
+	// StrictDecoding warns about fields of API responses the client does not know, which usually
+	// means a field was renamed on the API side and is decoded as its zero value. Responses are
+	// still decoded as usual.
+	StrictDecoding bool
+	// AccountIDParam is the name of the query parameter carrying the account ID of lookups, for
+	// gateways with their own naming conventions. Empty means DefaultAccountIDParam.
+	AccountIDParam string
 }
 
-func NewClient(host *string, token string) (*Client, error) {
-	c := Client{
-		HTTPClient: &http.Client{Timeout: 180 * time.Second},
-		HostURL:    models.DefaultHostURL,
+// DefaultAccountIDParam is the query parameter carrying the account ID of lookups unless configured
+// otherwise.
+const DefaultAccountIDParam = "accountID"
+
+// accountIDParam returns AccountIDParam, or DefaultAccountIDParam when it is empty.
+func (c *Client) accountIDParam() string {
+	if c.AccountIDParam == "" {
+		return DefaultAccountIDParam
+	}
+	return c.AccountIDParam
+}
+
+// LogSubsystem is the tflog subsystem DoRequest logs requests and responses to.
+const LogSubsystem = "zesty_api"
+
+// APIVersionHeader carries models.APIVersion on requests, and the version of the API on responses.
+const APIVersionHeader = "X-API-Version"
+
+// RequestIDHeaders lists the response headers that may carry the ID of a request, in order of
+// preference.
+var RequestIDHeaders = []string{"X-Request-ID", "X-Correlation-ID"}
+
+// requestID returns the value of the first of RequestIDHeaders set in header.
+func requestID(header http.Header) string {
+	for _, name := range RequestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
 	}
+	return ""
+}
 
+// ReservedHeaders lists the headers set by the client itself, which ExtraHeaders cannot override.
+var ReservedHeaders = []string{"Accept", APIVersionHeader, "Authorization", "Content-Type", IdempotencyKeyHeader, "User-Agent", "X-Api-Key"}
+
+// IsReservedHeader reports whether name is one of ReservedHeaders, ignoring case.
+func IsReservedHeader(name string) bool {
+	for _, reserved := range ReservedHeaders {
+		if strings.EqualFold(name, reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewClient returns a client for the API at host, defaulting to models.DefaultHostURL when host is
+// nil. Tests point the client at a local server by passing its URL as host. It is a shorthand for
+// NewClientWithOptions with WithHost and WithAuthHeader.
+func NewClient(host *string, token string) (*Client, error) {
+	opts := []Option{WithAuthHeader(token)}
 	if host != nil {
-		c.HostURL = *host
+		opts = append(opts, WithHost(*host))
 	}
+	return NewClientWithOptions(opts...)
+}
+
+// WithToken returns a copy of c sending token instead of its own, sharing its HTTP client, limiter and
+// retry budget.
+func (c *Client) WithToken(token string) *Client {
+	clone := *c
+	clone.Token = token
+	return &clone
+}
 
-	c.Token = token
+// endpoint returns the URL of the API endpoint at path, which starts with a slash.
+func (c *Client) endpoint(path string) string {
+	return c.HostURL + c.BasePath + path
+}
 
-	return &c, nil
+// NormalizeBasePath returns basePath with a single leading slash, no trailing slash and no repeated
+// slashes, such as /zesty/v2 for zesty/v2/. Empty paths and / normalize to "".
+func NormalizeBasePath(basePath string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.TrimSpace(basePath), "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(segments, "/")
 }
 
-func (c *Client) Validate() error {
-	url := fmt.Sprintf("%s/validate", c.HostURL)
-	req, err := http.NewRequest("GET", url, nil)
+// NormalizeHostURL checks that host is an absolute http or https URL and trims trailing slashes,
+// so endpoints can be appended with a single slash.
+func NormalizeHostURL(host string) (string, error) {
+	parsed, err := url.ParseRequestURI(host)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("invalid host URL %q: %w", host, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("invalid host URL %q: scheme must be http or https", host)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("invalid host URL %q: missing host name", host)
+	}
+	if strings.ContainsAny(host, "?# \t") {
+		return "", fmt.Errorf("invalid host URL %q: must not contain a query, fragment or whitespace", host)
 	}
+	return strings.TrimRight(host, "/"), nil
+}
 
-	_, err = c.DoRequest(req)
+// Validate checks the token against the API. Transient failures, as reported by IsTemporary, are
+// retried with jittered exponential backoff. Failures are returned as a *ValidationError, which wraps
+// the context's error when ctx is done while waiting to retry.
+func (c *Client) Validate(ctx context.Context) error {
+	_, err := c.Ping(ctx)
 	return err
 }
 
-func (c *Client) DoRequest(req *http.Request) ([]byte, error) {
+// Ping checks the token against the API like Validate, and also returns the round-trip latency of the
+// last request sent, for monitoring the responsiveness of the API. Retries and the waits between
+// them are not included. The latency is zero when no request was sent.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	url := c.endpoint("/validate")
+	backoff := c.ValidateBackoff
+
+	var latency time.Duration
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return latency, err
+		}
+
+		latency, err = c.timedDoRequest(req)
+		if err == nil {
+			return latency, nil
+		}
+		if attempt >= c.ValidateAttempts || !IsTemporary(err) || ctx.Err() != nil {
+			break
+		}
+		if !c.RetryBudget.Take() {
+			tflog.Warn(ctx, "Not retrying Zesty API validation, the retry budget is exhausted", map[string]any{
+				"attempt": attempt,
+				"error":   err.Error(),
+			})
+			break
+		}
+
+		wait := jitter(backoff)
+		logRetry(ctx, "Retrying Zesty API validation", attempt, err, wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return latency, &ValidationError{Attempts: attempt, Err: fmt.Errorf("%w while waiting to retry after: %v", ctx.Err(), err)}
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+
+	return latency, &ValidationError{Attempts: attempt, Err: err}
+}
+
+// timedDoRequest sends req once, discarding the response body, and returns how long the call took.
+// It does not apply Retry, as Ping retries on its own.
+func (c *Client) timedDoRequest(req *http.Request) (time.Duration, error) {
+	start := time.Now()
+	_, _, err := c.doRequest(req)
+	return time.Since(start), err
+}
+
+// isAcceptableStatus reports whether DoRequest treats statusCode as successful.
+func (c *Client) isAcceptableStatus(statusCode int) bool {
+	if len(c.AcceptableStatusCodes) == 0 {
+		return statusCode >= 200 && statusCode <= 299
+	}
+	return slices.Contains(c.AcceptableStatusCodes, statusCode)
+}
+
+// DoRequest sends req to the API and returns the response body and status code.
+// Responses with a status code outside AcceptableStatusCodes, any non-2xx by default, are
+// returned as an *APIError along with their status code and request ID; the status code is zero
+// when no response was received. Failed requests are retried as configured by Retry.
+func (c *Client) DoRequest(req *http.Request) ([]byte, int, error) {
+	return c.doRequestWithRetries(req)
+}
+
+// doRequest sends req to the API once, as described by DoRequest.
+func (c *Client) doRequest(req *http.Request) ([]byte, int, error) {
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(req.Context()); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	for name, value := range c.ExtraHeaders {
+		if IsReservedHeader(name) {
+			continue
+		}
+		req.Header.Set(name, value)
+	}
 	req.Header.Set("x-api-key", c.Token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(APIVersionHeader, models.APIVersion)
+	if req.Body != nil && req.Body != http.NoBody && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	ctx := tflog.NewSubsystem(req.Context(), LogSubsystem, tflog.WithLevel(c.LogLevel))
+	if c.Token != "" {
+		ctx = tflog.SubsystemMaskLogStrings(ctx, LogSubsystem, c.Token)
+	}
+	fields := map[string]any{
+		"method": req.Method,
+		"path":   req.URL.Path,
+	}
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Sending Zesty API request", fields, map[string]any{
+		"body": redactBody(requestBody(req)),
+	})
 
+	start := time.Now()
 	res, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer func() {
 		_ = res.Body.Close()
 	}()
 
-	body, err := io.ReadAll(res.Body)
+	reader := io.Reader(res.Body)
+	if c.MaxResponseBytes > 0 {
+		reader = io.LimitReader(res.Body, c.MaxResponseBytes+1)
+	}
+	body, err := io.ReadAll(reader)
 	if err != nil {
+		return nil, res.StatusCode, err
+	}
+	if c.MaxResponseBytes > 0 && int64(len(body)) > c.MaxResponseBytes {
+		return nil, res.StatusCode, fmt.Errorf("response body from %s %s exceeds the limit of %d bytes, check that the host points at the Zesty API", req.Method, req.URL.Path, c.MaxResponseBytes)
+	}
+
+	id := requestID(res.Header)
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Received Zesty API response", fields, map[string]any{
+		"request_id":  id,
+		"status_code": res.StatusCode,
+		"duration_ms": time.Since(start).Milliseconds(),
+		"body":        redactBody(body),
+	})
+
+	// An API of another version rejects payloads with errors that do not point at the version, so
+	// the version it reports is checked before the status code.
+	if version := res.Header.Get(APIVersionHeader); version != "" && !models.CompatibleAPIVersion(version) {
+		return nil, res.StatusCode, &VersionMismatchError{Expected: models.APIVersion, Actual: version}
+	}
+
+	if !c.isAcceptableStatus(res.StatusCode) {
+		return nil, res.StatusCode, &APIError{Method: req.Method, Path: req.URL.Path, StatusCode: res.StatusCode, Body: body, RequestID: id}
+	}
+
+	return body, res.StatusCode, err
+}
+
+// accountEnvelope is the shape of API versions that wrap the account in a data field.
+type accountEnvelope struct {
+	Data *models.Account `json:"data"`
+}
+
+// decodeAccount decodes an account response body like decodeBody, accepting both the bare account
+// and an account wrapped in a data field. The bare decode is tried first, and the envelope is only
+// used when it yields no account ID.
+func (c *Client) decodeAccount(ctx context.Context, body []byte, statusCode int) (*models.Account, error) {
+	account := models.Account{}
+	if err := unmarshalBody(body, statusCode, &account); err != nil {
 		return nil, err
 	}
+	if account.AccountID != "" {
+		c.checkUnknownFields(ctx, body, &account)
+		return &account, nil
+	}
 
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+	envelope := accountEnvelope{}
+	if err := unmarshalBody(body, statusCode, &envelope); err == nil && envelope.Data != nil {
+		c.checkUnknownFields(ctx, body, &envelope)
+		return envelope.Data, nil
 	}
+	c.checkUnknownFields(ctx, body, &account)
+	return &account, nil
+}
 
-	return body, err
+// decodeBody unmarshals a JSON response body into v with unmarshalBody. With StrictDecoding, it
+// also warns about fields of the body v has no field for.
+func (c *Client) decodeBody(ctx context.Context, body []byte, statusCode int, v any) error {
+	if err := unmarshalBody(body, statusCode, v); err != nil {
+		return err
+	}
+	c.checkUnknownFields(ctx, body, v)
+	return nil
 }
 
-func (c *Client) CreateAccount(payload models.Payload) (*models.Account, error) {
+// unmarshalBody unmarshals a JSON response body into v. Empty bodies, as sent with
+// 202 Accepted or 204 No Content, leave v untouched. Bodies that are not JSON at all
+// return a DecodeError.
+func unmarshalBody(body []byte, statusCode int, v any) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		if !json.Valid(body) {
+			return &DecodeError{StatusCode: statusCode, Body: body, Err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+// checkUnknownFields warns about the fields of body that v, the decoded body, has no field for,
+// naming each by its path in the body. It does nothing unless StrictDecoding is set.
+func (c *Client) checkUnknownFields(ctx context.Context, body []byte, v any) {
+	if !c.StrictDecoding || len(bytes.TrimSpace(body)) == 0 {
+		return
+	}
+
+	var raw any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return
+	}
+	fields := unknownFields(raw, reflect.TypeOf(v), "")
+	if len(fields) == 0 {
+		return
+	}
+	tflog.Warn(ctx, "Zesty API response has unexpected fields", map[string]any{
+		"fields": fields,
+	})
+}
+
+var (
+	accountType     = reflect.TypeOf(models.Account{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// accountFields has the fields of models.Account without its methods.
+type accountFields models.Account
+
+// unknownFields returns the paths of the object keys in raw, a decoded JSON value, that typ has no
+// field for, prefixed with prefix. Keys match fields case-insensitively, as in encoding/json.
+// Accounts are compared by their fields, while values of other types that decode themselves are
+// not looked into.
+func unknownFields(raw any, typ reflect.Type, prefix string) []string {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == accountType {
+		typ = reflect.TypeOf(accountFields{})
+	} else if reflect.PointerTo(typ).Implements(unmarshalerType) {
+		return nil
+	}
+
+	var unknown []string
+	switch typ.Kind() {
+	case reflect.Struct:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return nil
+		}
+		fields := jsonFields(typ)
+		for _, key := range sortedKeys(object) {
+			field, ok := lookupJSONField(fields, key)
+			if !ok {
+				unknown = append(unknown, prefix+key)
+				continue
+			}
+			unknown = append(unknown, unknownFields(object[key], field, prefix+key+".")...)
+		}
+	case reflect.Map:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(object) {
+			unknown = append(unknown, unknownFields(object[key], typ.Elem(), prefix+key+".")...)
+		}
+	case reflect.Slice, reflect.Array:
+		list, ok := raw.([]any)
+		if !ok {
+			return nil
+		}
+		for i, element := range list {
+			unknown = append(unknown, unknownFields(element, typ.Elem(), prefix+strconv.Itoa(i)+".")...)
+		}
+	}
+	return unknown
+}
+
+// jsonFields returns the types of the fields of typ, a struct, by their JSON names. The fields of
+// embedded structs are included unless a field of typ has the same name.
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	var embedded []reflect.Type
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				embedded = append(embedded, fieldType)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+
+	for _, fieldType := range embedded {
+		for name, embeddedType := range jsonFields(fieldType) {
+			if _, ok := fields[name]; !ok {
+				fields[name] = embeddedType
+			}
+		}
+	}
+	return fields
+}
+
+// lookupJSONField returns the type of the field named key, preferring an exact match over a
+// case-insensitive one.
+func lookupJSONField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if field, ok := fields[key]; ok {
+		return field, true
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// sortedKeys returns the keys of object in lexical order.
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *Client) CreateAccount(ctx context.Context, payload models.Payload) (*models.Account, error) {
+	if c.ReadOnly {
+		logReadOnly(ctx, "create", payload)
+		return accountFromPayload(payload), nil
+	}
+
 	rb, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/account", c.HostURL)
-	req, err := http.NewRequest("POST", url, bytes.NewReader(rb))
+	url := c.endpoint("/account")
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(rb))
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set(IdempotencyKeyHeader, idempotencyKey(ctx, rb))
 
-	body, err := c.DoRequest(req)
+	body, statusCode, err := c.DoRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	account := models.Account{}
-	err = json.Unmarshal(body, &account)
+	account, err := c.decodeAccount(ctx, body, statusCode)
 	if err != nil {
 		return nil, err
 	}
 
-	return &account, nil
+	tflog.Debug(ctx, "Created Zesty account", map[string]any{
+		"account_id": account.AccountID,
+		"created":    statusCode == http.StatusCreated,
+	})
+
+	return account, nil
+}
+
+// MergePatchContentType is the content type of the JSON merge patches sent by UpdateAccountPartial.
+const MergePatchContentType = "application/merge-patch+json"
+
+// UpdateAccountPartial updates an account with a PATCH request holding only the fields that differ
+// between prior, the payload the account was last written with, and payload, as computed by
+// models.PayloadPatch. Unlike UpdateAccount, fields the provider does not manage are left untouched
+// on the server.
+func (c *Client) UpdateAccountPartial(ctx context.Context, prior models.Payload, payload models.Payload) (*models.Account, error) {
+	if c.ReadOnly {
+		logReadOnly(ctx, "update", payload)
+		return accountFromPayload(payload), nil
+	}
+
+	patch, err := models.PayloadPatch(prior, payload)
+	if err != nil {
+		return nil, err
+	}
+	rb, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.endpoint("/account")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", MergePatchContentType)
+
+	body, statusCode, err := c.DoRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := c.decodeAccount(ctx, body, statusCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
 }
 
-func (c *Client) DeleteAccount(payload models.Payload) error {
+// SetProductActive activates or deactivates a single product of an account with a PATCH request
+// holding only that product's active flag, leaving its values and the rest of the account untouched.
+// It returns the updated account. In ReadOnly mode, it reads the account and returns it with the
+// product's active flag changed.
+func (c *Client) SetProductActive(ctx context.Context, accountID string, product models.Product, active bool) (*models.Account, error) {
+	if c.ReadOnly {
+		// The PATCH leaves the rest of the account untouched, so the account it would return is
+		// read from the API.
+		account, err := c.GetAccount(ctx, accountID)
+		if err != nil {
+			return nil, err
+		}
+		logReadOnly(ctx, "update", models.Payload{AccountID: accountID})
+		if account.Products == nil {
+			account.Products = map[models.Product]models.ProductDetails{}
+		}
+		details := account.Products[product]
+		details.Active = active
+		account.Products[product] = details
+		return account, nil
+	}
+
+	rb, err := json.Marshal(map[string]any{
+		"accountID": accountID,
+		"products": map[models.Product]any{
+			product: map[string]any{"active": active},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.endpoint("/account")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(rb))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", MergePatchContentType)
+
+	body, statusCode, err := c.DoRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.decodeAccount(ctx, body, statusCode)
+}
+
+// DeletionMode selects how DeleteAccountWithMode removes an account.
+type DeletionMode string
+
+const (
+	// DeletionModeDefault sends no mode, leaving the choice to the API.
+	DeletionModeDefault DeletionMode = ""
+	// DeletionModeSoft deactivates the account, keeping its data.
+	DeletionModeSoft DeletionMode = "soft"
+	// DeletionModeHard removes the account and purges its data.
+	DeletionModeHard DeletionMode = "hard"
+)
+
+// DeletionModeParam is the query parameter carrying the mode of a delete request.
+const DeletionModeParam = "mode"
+
+func (c *Client) DeleteAccount(ctx context.Context, payload models.Payload) error {
+	return c.DeleteAccountWithMode(ctx, payload, DeletionModeDefault)
+}
+
+// DeleteAccountWithMode deletes an account like DeleteAccount, asking the API for a soft or hard
+// delete with the DeletionModeParam query parameter unless mode is DeletionModeDefault.
+func (c *Client) DeleteAccountWithMode(ctx context.Context, payload models.Payload, mode DeletionMode) error {
+	if c.ReadOnly {
+		logReadOnly(ctx, "delete", payload)
+		return nil
+	}
+
 	rb, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
-	url := fmt.Sprintf("%s/account", c.HostURL)
-	req, err := http.NewRequest("DELETE", url, bytes.NewReader(rb))
+	endpoint := c.endpoint("/account")
+	if mode != DeletionModeDefault {
+		endpoint += "?" + url.Values{DeletionModeParam: {string(mode)}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", endpoint, bytes.NewReader(rb))
 	if err != nil {
 		return err
 	}
 
-	_, err = c.DoRequest(req)
+	_, _, err = c.DoRequest(req)
 	return err
 }
 
-func (c *Client) GetAccounts() (*[]models.Account, error) {
-	url := fmt.Sprintf("%s/accounts", c.HostURL)
-	req, err := http.NewRequest("GET", url, nil)
+// WaitForAccountDeleted polls GetAccount with PollUntil, starting DeletePollInterval apart, until the
+// API reports accountID as not found, confirming that a delete the API accepted, possibly as a soft
+// delete, went through. Temporary errors are polled through, and other errors are returned as they
+// are. When ctx is done first, the returned error wraps the context's error. In read-only mode
+// nothing was deleted, so it returns at once.
+func (c *Client) WaitForAccountDeleted(ctx context.Context, accountID string) error {
+	if c.ReadOnly {
+		return nil
+	}
+
+	var polls int
+	err := PollUntil(ctx, c.DeletePollInterval, 0, func() (bool, error) {
+		polls++
+		_, err := c.GetAccount(ctx, accountID)
+		if IsNotFound(err) {
+			return true, nil
+		}
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if err != nil && !IsTemporary(err) {
+			return false, err
+		}
+
+		tflog.Debug(ctx, "Waiting for Zesty account deletion", map[string]any{
+			"account_id": accountID,
+			"polls":      polls,
+		})
+		return false, nil
+	})
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("account %s still exists after %d checks: %w", accountID, polls, ctx.Err())
+	}
+	return err
+}
+
+// AccountsFilter narrows down the accounts returned by GetAccounts.
+// Empty fields are not applied, and set fields are combined with AND semantics.
+type AccountsFilter struct {
+	CloudProvider  models.CloudProvider
+	Product        models.Product
+	OrganizationID int64
+	// OnboardingStatus is also applied client-side, as not every API version filters on it.
+	OnboardingStatus models.OnboardingStatus
+	// UpdatedAfter, unless zero, only matches accounts updated after it. Like OnboardingStatus it is
+	// also applied client-side.
+	UpdatedAfter time.Time
+}
+
+func (f AccountsFilter) query() url.Values {
+	query := url.Values{}
+	if f.CloudProvider != "" {
+		query.Set("cloudProvider", string(f.CloudProvider))
+	}
+	if f.Product != "" {
+		query.Set("product", string(f.Product))
+	}
+	if f.OrganizationID != 0 {
+		query.Set("organizationID", strconv.FormatInt(f.OrganizationID, 10))
+	}
+	if f.OnboardingStatus != "" {
+		query.Set("onboardingStatus", string(f.OnboardingStatus))
+	}
+	if !f.UpdatedAfter.IsZero() {
+		query.Set("updatedAfter", f.UpdatedAfter.UTC().Format(time.RFC3339))
+	}
+	return query
+}
+
+// matches reports whether account passes the filters that are also applied client-side.
+func (f AccountsFilter) matches(account models.Account) bool {
+	if f.OnboardingStatus != "" && account.OnboardingStatus != f.OnboardingStatus {
+		return false
+	}
+	if !f.UpdatedAfter.IsZero() && !account.UpdatedAt.After(f.UpdatedAfter) {
+		return false
+	}
+	return true
+}
+
+func (c *Client) GetAccounts(ctx context.Context, filter AccountsFilter) (*[]models.Account, error) {
+	endpoint := c.endpoint("/accounts")
+	if query := filter.query(); len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.DoRequest(req)
+	body, statusCode, err := c.DoRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
 	account := []models.Account{}
-	err = json.Unmarshal(body, &account)
+	err = c.decodeBody(ctx, body, statusCode, &account)
 	if err != nil {
 		return nil, err
 	}
 
-	return &account, nil
+	matching := []models.Account{}
+	for _, a := range account {
+		if filter.matches(a) {
+			matching = append(matching, a)
+		}
+	}
+
+	return &matching, nil
 }
 
-func (c *Client) GetAccount(accountID string) (*models.Account, error) {
-	url := fmt.Sprintf("%s/account?accountID=%s", c.HostURL, accountID)
-	req, err := http.NewRequest("GET", url, nil)
+// GetAccountsModifiedSince returns the accounts updated after t, for incremental syncs.
+func (c *Client) GetAccountsModifiedSince(ctx context.Context, t time.Time) (*[]models.Account, error) {
+	return c.GetAccounts(ctx, AccountsFilter{UpdatedAfter: t})
+}
+
+func (c *Client) GetAccount(ctx context.Context, accountID string) (*models.Account, error) {
+	return c.getAccount(ctx, url.Values{c.accountIDParam(): {accountID}})
+}
+
+// GetAccountInOrg looks up an account within a specific organization, for setups where
+// account IDs are not unique across organizations.
+func (c *Client) GetAccountInOrg(ctx context.Context, orgID int64, accountID string) (*models.Account, error) {
+	return c.getAccount(ctx, url.Values{
+		c.accountIDParam(): {accountID},
+		"organizationID":   {strconv.FormatInt(orgID, 10)},
+	})
+}
+
+// ExternalIDParam is the query parameter carrying the external ID of GetAccountByExternalID lookups.
+const ExternalIDParam = "externalID"
+
+// GetAccountByExternalID looks up an account by its external ID, for automation that does not know
+// the Zesty account ID. Like GetAccount, it returns an error satisfying IsNotFound when no account
+// has the external ID.
+func (c *Client) GetAccountByExternalID(ctx context.Context, externalID string) (*models.Account, error) {
+	return c.getAccount(ctx, url.Values{ExternalIDParam: {externalID}})
+}
+
+func (c *Client) getAccount(ctx context.Context, query url.Values) (*models.Account, error) {
+	endpoint := c.endpoint("/account") + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.DoRequest(req)
+	body, statusCode, err := c.DoRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	account := models.Account{}
-	err = json.Unmarshal(body, &account)
+	account, err := c.decodeAccount(ctx, body, statusCode)
 	if err != nil {
 		return nil, err
 	}
 
-	return &account, nil
+	return account, nil
 }
 
-func (c *Client) UpdateAccount(payload models.Payload) (*models.Account, error) {
+// GetAccountHistory returns the snapshots the API recorded of an account's products, oldest first.
+// History is served under a path versioned with models.APIVersion, as its shape may change
+// independently of the other endpoints.
+func (c *Client) GetAccountHistory(ctx context.Context, accountID string) ([]models.AccountSnapshot, error) {
+	endpoint := c.endpoint("/"+models.APIVersion+"/account/history") + "?" + url.Values{c.accountIDParam(): {accountID}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, statusCode, err := c.DoRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []models.AccountSnapshot{}
+	if err := c.decodeBody(ctx, body, statusCode, &snapshots); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+
+	return snapshots, nil
+}
+
+func (c *Client) UpdateAccount(ctx context.Context, payload models.Payload) (*models.Account, error) {
+	if c.ReadOnly {
+		logReadOnly(ctx, "update", payload)
+		return accountFromPayload(payload), nil
+	}
+
 	rb, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/account", c.HostURL)
-	req, err := http.NewRequest("PUT", url, bytes.NewReader(rb))
+	url := c.endpoint("/account")
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewReader(rb))
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := c.DoRequest(req)
+	body, statusCode, err := c.DoRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	account := models.Account{}
-	err = json.Unmarshal(body, &account)
+	account, err := c.decodeAccount(ctx, body, statusCode)
 	if err != nil {
 		return nil, err
 	}
 
-	return &account, nil
+	return account, nil
 }
@@ -2,16 +2,23 @@ package client_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/zesty-co/terraform-provider-zesty/internal/client"
 	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+	"golang.org/x/time/rate"
 )
 
 const AUTH_HEADER string = "X-Api-Key"
@@ -38,6 +45,38 @@ func TestNewClient(t *testing.T) {
 			expectedURL: "http://customhost:1234",
 			expectError: false,
 		},
+		{
+			name:        "trailing slash is trimmed",
+			host:        func() *string { s := "https://api.example.com/kompass-platform/"; return &s }(),
+			token:       "testtoken",
+			expectedURL: "https://api.example.com/kompass-platform",
+			expectError: false,
+		},
+		{
+			name:        "missing scheme",
+			host:        func() *string { s := "api.example.com"; return &s }(),
+			expectError: true,
+		},
+		{
+			name:        "unsupported scheme",
+			host:        func() *string { s := "ftp://api.example.com"; return &s }(),
+			expectError: true,
+		},
+		{
+			name:        "missing host name",
+			host:        func() *string { s := "https:///kompass-platform"; return &s }(),
+			expectError: true,
+		},
+		{
+			name:        "trailing junk",
+			host:        func() *string { s := "https://api.example.com/?x=1"; return &s }(),
+			expectError: true,
+		},
+		{
+			name:        "empty host",
+			host:        func() *string { s := ""; return &s }(),
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -54,6 +93,9 @@ func TestNewClient(t *testing.T) {
 				assert.Equal(t, tt.token, c.Token)
 				assert.NotNil(t, c.HTTPClient)
 				assert.Equal(t, 180*time.Second, c.HTTPClient.Timeout)
+				assert.Equal(t, client.DefaultTimeout, c.HTTPClient.Timeout)
+				assert.Equal(t, client.UserAgentPrefix, c.UserAgent)
+				assert.Equal(t, rate.Inf, c.Limiter.Limit())
 			}
 		})
 	}
@@ -65,6 +107,7 @@ func TestClient_DoRequest(t *testing.T) {
 		token            string
 		serverHandler    http.HandlerFunc
 		expectedBody     []byte
+		expectedStatus   int
 		expectedErrorMsg string
 		method           string
 		path             string
@@ -85,6 +128,7 @@ func TestClient_DoRequest(t *testing.T) {
 			method:           http.MethodGet,
 			path:             "/test",
 			expectedBody:     []byte(`{"message":"success"}`),
+			expectedStatus:   http.StatusOK,
 			expectedErrorMsg: "",
 		},
 		{
@@ -103,6 +147,7 @@ func TestClient_DoRequest(t *testing.T) {
 			path:             "/create",
 			requestBody:      bytes.NewReader([]byte(`{"key":"value"}`)),
 			expectedBody:     []byte(`{"id":"123"}`),
+			expectedStatus:   http.StatusCreated,
 			expectedErrorMsg: "",
 		},
 		{
@@ -115,6 +160,7 @@ func TestClient_DoRequest(t *testing.T) {
 			method:           http.MethodGet,
 			path:             "/forbidden",
 			expectedBody:     nil,
+			expectedStatus:   http.StatusForbidden,
 			expectedErrorMsg: "status: 403, body: {\"error\":\"forbidden\"}",
 		},
 		{
@@ -127,8 +173,34 @@ func TestClient_DoRequest(t *testing.T) {
 			method:           http.MethodDelete,
 			path:             "/nonexistent",
 			expectedBody:     nil,
+			expectedStatus:   http.StatusNotFound,
 			expectedErrorMsg: "status: 404, body: {\"error\":\"not found\"}",
 		},
+		{
+			name:  "successful request with status accepted",
+			token: "goodtoken",
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"status":"pending"}`))
+			},
+			method:           http.MethodPut,
+			path:             "/accepted",
+			expectedBody:     []byte(`{"status":"pending"}`),
+			expectedStatus:   http.StatusAccepted,
+			expectedErrorMsg: "",
+		},
+		{
+			name:  "successful request with status no content",
+			token: "goodtoken",
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			method:           http.MethodDelete,
+			path:             "/nocontent",
+			expectedBody:     []byte{},
+			expectedStatus:   http.StatusNoContent,
+			expectedErrorMsg: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -146,7 +218,8 @@ func TestClient_DoRequest(t *testing.T) {
 			req, err := http.NewRequest(tt.method, server.URL+tt.path, tt.requestBody)
 			assert.NoError(t, err)
 
-			body, err := c.DoRequest(req)
+			body, statusCode, err := c.DoRequest(req)
+			assert.Equal(t, tt.expectedStatus, statusCode)
 
 			if tt.expectedErrorMsg != "" {
 				assert.Error(t, err)
@@ -165,8 +238,9 @@ func TestClient_DoRequest(t *testing.T) {
 		c.HTTPClient = &http.Client{Timeout: 100 * time.Millisecond}
 
 		req, _ := http.NewRequest("GET", nonExistentURL+"/test", nil)
-		_, err := c.DoRequest(req)
+		_, statusCode, err := c.DoRequest(req)
 		assert.Error(t, err)
+		assert.Zero(t, statusCode)
 	})
 }
 
@@ -212,7 +286,7 @@ func TestClient_Validate(t *testing.T) {
 			defer server.Close()
 
 			c, _ := client.NewClient(&server.URL, tt.token)
-			err := c.Validate()
+			err := c.Validate(context.Background())
 
 			if tt.expectedErrorMsg != "" {
 				assert.Error(t, err)
@@ -224,6 +298,27 @@ func TestClient_Validate(t *testing.T) {
 	}
 }
 
+func TestClient_Ping(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/validate", r.URL.Path)
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	latency, err := c.Ping(context.Background())
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, latency, 10*time.Millisecond)
+
+	status.Store(http.StatusForbidden)
+	latency, err = c.Ping(context.Background())
+	assert.True(t, client.IsUnauthorized(err))
+	assert.GreaterOrEqual(t, latency, 10*time.Millisecond)
+}
+
 func TestClient_CreateAccount(t *testing.T) {
 	type testCase struct {
 		name             string
@@ -307,7 +402,7 @@ func TestClient_CreateAccount(t *testing.T) {
 			defer server.Close()
 
 			c, _ := client.NewClient(&server.URL, tt.token)
-			account, err := c.CreateAccount(tt.payload)
+			account, err := c.CreateAccount(context.Background(), tt.payload)
 
 			if tt.expectedErrorMsg != "" {
 				assert.Error(t, err)
@@ -341,19 +436,32 @@ func TestClient_DeleteAccount(t *testing.T) {
 				assert.Equal(t, "DELETE", r.Method)
 				assert.Equal(t, "/account", r.URL.Path)
 				assert.Equal(t, "delete-token", r.Header.Get(AUTH_HEADER))
+				assert.Empty(t, r.URL.RawQuery)
 
-				var p models.Payload
+				var p map[string]json.RawMessage
 				err := json.NewDecoder(r.Body).Decode(&p)
 				if !assert.NoError(t, err) {
 					http.Error(w, "bad request body for delete", http.StatusBadRequest)
 					return
 				}
-				assert.Equal(t, "acc123", p.AccountID)
+				assert.Equal(t, `"acc123"`, string(p["accountID"]))
+				assert.JSONEq(t, `{}`, string(p["products"]))
 
 				w.WriteHeader(http.StatusOK)
 			},
 			expectedErrorMsg: "",
 		},
+		{
+			name:           "successful deletion with no content",
+			token:          "delete-token",
+			organizationID: 1,
+			accountID:      "acc123",
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "DELETE", r.Method)
+				w.WriteHeader(http.StatusNoContent)
+			},
+			expectedErrorMsg: "",
+		},
 		{
 			name:           "server returns error",
 			token:          "del-err-token",
@@ -376,7 +484,7 @@ func TestClient_DeleteAccount(t *testing.T) {
 			c, _ := client.NewClient(&server.URL, tt.token)
 
 			payload := models.Payload{AccountID: tt.accountID}
-			err := c.DeleteAccount(payload)
+			err := c.DeleteAccount(context.Background(), payload)
 
 			if tt.expectedErrorMsg != "" {
 				assert.Error(t, err)
@@ -388,6 +496,118 @@ func TestClient_DeleteAccount(t *testing.T) {
 	}
 }
 
+func TestClient_DeleteAccountWithMode(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/account", r.URL.Path)
+		query = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	payload := models.Payload{AccountID: "acc123"}
+
+	assert.NoError(t, c.DeleteAccountWithMode(context.Background(), payload, client.DeletionModeSoft))
+	assert.Equal(t, url.Values{client.DeletionModeParam: {"soft"}}, query)
+
+	assert.NoError(t, c.DeleteAccountWithMode(context.Background(), payload, client.DeletionModeHard))
+	assert.Equal(t, url.Values{client.DeletionModeParam: {"hard"}}, query)
+
+	assert.NoError(t, c.DeleteAccountWithMode(context.Background(), payload, client.DeletionModeDefault))
+	assert.Empty(t, query)
+
+	// Modes are escaped rather than relying on callers to validate them.
+	assert.NoError(t, c.DeleteAccountWithMode(context.Background(), payload, client.DeletionMode("soft&purge=true")))
+	assert.Equal(t, url.Values{client.DeletionModeParam: {"soft&purge=true"}}, query)
+}
+
+func TestClient_WaitForAccountDeleted(t *testing.T) {
+	tests := []struct {
+		name             string
+		lingers          int
+		status           int
+		timeout          time.Duration
+		expectedPolls    int
+		expectedErrorMsg string
+	}{
+		{name: "already gone", expectedPolls: 1},
+		{name: "lingers for a couple of polls", lingers: 2, expectedPolls: 3},
+		{name: "polls through temporary errors", lingers: 2, status: http.StatusServiceUnavailable, expectedPolls: 3},
+		{
+			name:             "never disappears",
+			lingers:          1000,
+			timeout:          50 * time.Millisecond,
+			expectedErrorMsg: "account acc123 still exists after",
+		},
+		{
+			name:             "unexpected error",
+			lingers:          1000,
+			status:           http.StatusForbidden,
+			expectedPolls:    1,
+			expectedErrorMsg: "GET /account: status: 403",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "acc123", r.URL.Query().Get("accountID"))
+				polls++
+				if polls > tt.lingers {
+					http.NotFound(w, r)
+					return
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+			}))
+			defer server.Close()
+
+			c, _ := client.NewClient(&server.URL, "testtoken")
+			c.DeletePollInterval = time.Millisecond
+
+			ctx := context.Background()
+			if tt.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			err := c.WaitForAccountDeleted(ctx, "acc123")
+			if tt.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tt.expectedErrorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectedPolls != 0 {
+				assert.Equal(t, tt.expectedPolls, polls)
+			}
+		})
+	}
+}
+
+func TestClient_WaitForAccountDeleted_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	c.DeletePollInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	err := c.WaitForAccountDeleted(ctx, "acc123")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.EqualError(t, err, "account acc123 still exists after 1 checks: context canceled")
+}
 func TestClient_GetAccount(t *testing.T) {
 	type testCase struct {
 		name             string
@@ -448,7 +668,7 @@ func TestClient_GetAccount(t *testing.T) {
 			defer server.Close()
 
 			c, _ := client.NewClient(&server.URL, tt.token)
-			account, err := c.GetAccount(tt.accountID)
+			account, err := c.GetAccount(context.Background(), tt.accountID)
 
 			if tt.expectedErrorMsg != "" {
 				assert.Error(t, err)
@@ -462,6 +682,59 @@ func TestClient_GetAccount(t *testing.T) {
 	}
 }
 
+func TestClient_GetAccountByExternalID(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/account", r.URL.Path)
+		query = r.URL.Query()
+		if query.Get(client.ExternalIDParam) != "f1f0a7f7-a523-4197-9e19-ffd205a5bc20" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"accountID": "acc123", "cloudProvider": "AWS", "additionalData": {"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"}}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	account, err := c.GetAccountByExternalID(context.Background(), "f1f0a7f7-a523-4197-9e19-ffd205a5bc20")
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"externalID": {"f1f0a7f7-a523-4197-9e19-ffd205a5bc20"}}, query)
+	assert.Equal(t, "acc123", account.AccountID)
+	assert.Equal(t, models.AWS, account.CloudProvider)
+
+	account, err = c.GetAccountByExternalID(context.Background(), "unknown")
+	assert.True(t, client.IsNotFound(err), err)
+	assert.Nil(t, account)
+	assert.Equal(t, url.Values{"externalID": {"unknown"}}, query)
+}
+
+func TestClient_GetAccount_AccountIDParam(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"accountID": "acc123"}`))
+	}))
+	defer server.Close()
+
+	c, err := client.NewClientWithOptions(client.WithHost(server.URL), client.WithAccountIDParam("account_id"))
+	assert.NoError(t, err)
+
+	_, err = c.GetAccount(context.Background(), "acc123")
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"account_id": {"acc123"}}, query)
+
+	_, err = c.GetAccountInOrg(context.Background(), 7, "acc123")
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"account_id": {"acc123"}, "organizationID": {"7"}}, query)
+
+	_, err = client.NewClientWithOptions(client.WithAccountIDParam(" "))
+	assert.EqualError(t, err, "account ID parameter name must not be empty")
+}
+
 func TestClient_UpdateAccount(t *testing.T) {
 	type testCase struct {
 		name             string
@@ -545,7 +818,7 @@ func TestClient_UpdateAccount(t *testing.T) {
 			defer server.Close()
 
 			c, _ := client.NewClient(&server.URL, tt.token)
-			account, err := c.UpdateAccount(tt.payload)
+			account, err := c.UpdateAccount(context.Background(), tt.payload)
 
 			if tt.expectedErrorMsg != "" {
 				assert.Error(t, err)
@@ -558,3 +831,1097 @@ func TestClient_UpdateAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ContextTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "timeout-token")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	account, err := c.GetAccount(ctx, "acc123")
+
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, account)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_GetAccounts(t *testing.T) {
+	type testCase struct {
+		name             string
+		filter           client.AccountsFilter
+		expectedQuery    url.Values
+		expectedAccounts *[]models.Account
+		expectedErrorMsg string
+	}
+
+	sampleAccounts := &[]models.Account{
+		{
+			AccountID:     "acc123",
+			CloudProvider: models.AWS,
+			Products: map[models.Product]models.ProductDetails{
+				models.Kompass: {Active: true},
+			},
+		},
+	}
+	sampleAccountsBytes, _ := json.Marshal(sampleAccounts)
+
+	tests := []testCase{
+		{
+			name:             "unfiltered",
+			filter:           client.AccountsFilter{},
+			expectedQuery:    url.Values{},
+			expectedAccounts: sampleAccounts,
+		},
+		{
+			name:             "filtered by cloud provider",
+			filter:           client.AccountsFilter{CloudProvider: models.AWS},
+			expectedQuery:    url.Values{"cloudProvider": {"AWS"}},
+			expectedAccounts: sampleAccounts,
+		},
+		{
+			name:   "filtered by cloud provider and product",
+			filter: client.AccountsFilter{CloudProvider: models.AWS, Product: models.Kompass},
+			expectedQuery: url.Values{
+				"cloudProvider": {"AWS"},
+				"product":       {"Kompass"},
+			},
+			expectedAccounts: sampleAccounts,
+		},
+		{
+			name:             "filtered by organization",
+			filter:           client.AccountsFilter{OrganizationID: 42},
+			expectedQuery:    url.Values{"organizationID": {"42"}},
+			expectedAccounts: sampleAccounts,
+		},
+		{
+			name:             "filtered by onboarding status without matches",
+			filter:           client.AccountsFilter{OnboardingStatus: "Failed"},
+			expectedQuery:    url.Values{"onboardingStatus": {"Failed"}},
+			expectedAccounts: &[]models.Account{},
+		},
+		{
+			name:             "server returns error",
+			filter:           client.AccountsFilter{Product: models.CM},
+			expectedQuery:    url.Values{"product": {"CM"}},
+			expectedErrorMsg: "status: 500, body: internal error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "GET", r.Method)
+				assert.Equal(t, "/accounts", r.URL.Path)
+				assert.Equal(t, tt.expectedQuery, r.URL.Query())
+
+				if tt.expectedErrorMsg != "" {
+					w.WriteHeader(http.StatusInternalServerError)
+					_, _ = w.Write([]byte("internal error"))
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(sampleAccountsBytes)
+			}))
+			defer server.Close()
+
+			c, _ := client.NewClient(&server.URL, "list-token")
+			accounts, err := c.GetAccounts(context.Background(), tt.filter)
+
+			if tt.expectedErrorMsg != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErrorMsg)
+				assert.Nil(t, accounts)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAccounts, accounts)
+			}
+		})
+	}
+}
+
+func TestClient_GetAccounts_OnboardingStatusFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server ignores the onboardingStatus parameter.
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[
+			{"AccountID": "acc1", "OnboardingStatus": "Completed"},
+			{"AccountID": "acc2", "OnboardingStatus": "Failed"},
+			{"AccountID": "acc3", "OnboardingStatus": "Failed"}
+		]`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "list-token")
+	accounts, err := c.GetAccounts(context.Background(), client.AccountsFilter{OnboardingStatus: "Failed"})
+	assert.NoError(t, err)
+	assert.Equal(t, &[]models.Account{
+		{AccountID: "acc2", OnboardingStatus: "Failed"},
+		{AccountID: "acc3", OnboardingStatus: "Failed"},
+	}, accounts)
+}
+
+func TestClient_GetAccountsModifiedSince(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/accounts", r.URL.Path)
+		assert.Equal(t, "2024-03-02T06:00:00Z", r.URL.Query().Get("updatedAfter"))
+
+		// The server ignores the updatedAfter parameter.
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[
+			{"AccountID": "acc1", "updatedAt": "2024-03-01T10:00:00Z"},
+			{"AccountID": "acc2", "updatedAt": "2024-03-02T06:00:00Z"},
+			{"AccountID": "acc3", "updatedAt": "2024-03-03T10:00:00Z"},
+			{"AccountID": "acc4"}
+		]`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "list-token")
+	since := time.Date(2024, 3, 2, 8, 0, 0, 0, time.FixedZone("IST", 2*60*60))
+	accounts, err := c.GetAccountsModifiedSince(context.Background(), since)
+	assert.NoError(t, err)
+	assert.Equal(t, &[]models.Account{
+		{AccountID: "acc3", UpdatedAt: time.Date(2024, 3, 3, 10, 0, 0, 0, time.UTC)},
+	}, accounts)
+}
+
+func TestClient_GetAccount_Timestamps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"accountID":"acc123","createdAt":"2024-03-01T10:30:00Z","updatedAt":"2024-03-02T08:15:45Z"}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "token")
+	account, err := c.GetAccount(context.Background(), "acc123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), account.CreatedAt)
+	assert.Equal(t, time.Date(2024, 3, 2, 8, 15, 45, 0, time.UTC), account.UpdatedAt)
+}
+
+func TestClient_GetAccount_OrganizationID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"accountID":"acc123","cloudProvider":"AWS","organizationID":42}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "token")
+	account, err := c.GetAccount(context.Background(), "acc123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), account.OrganizationID)
+}
+
+func TestClient_CreateAccount_ProductValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var raw map[string]any
+		assert.NoError(t, json.Unmarshal(body, &raw))
+		products := raw["products"].(map[string]any)
+		assert.Equal(t, map[string]any{"threshold": float64(80), "mode": "aggressive"}, products["Kompass"].(map[string]any)["values"])
+		assert.NotContains(t, products["CM"].(map[string]any), "values")
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "token")
+	_, err := c.CreateAccount(context.Background(), models.Payload{
+		AccountID: "acc123",
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {Active: true, Values: map[string]any{"threshold": 80, "mode": "aggressive"}},
+			models.CM:      {Active: true},
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func TestClient_ExtraHeaders(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+	c.ExtraHeaders = map[string]string{
+		"X-Tenant-ID":     "tenant-1",
+		"x-api-key":       "other-token",
+		"User-Agent":      "curl/8.0",
+		"idempotency-key": "fixed",
+	}
+
+	_, err = c.CreateAccount(context.Background(), models.Payload{AccountID: "acc123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant-1", header.Get("X-Tenant-ID"))
+	assert.Equal(t, []string{"testtoken"}, header.Values("X-Api-Key"))
+	assert.Equal(t, client.UserAgentPrefix, header.Get("User-Agent"))
+	assert.NotEqual(t, "fixed", header.Get(client.IdempotencyKeyHeader))
+	assert.NotEmpty(t, header.Get(client.IdempotencyKeyHeader))
+}
+
+func TestClient_ContentHeaders(t *testing.T) {
+	headers := map[string]http.Header{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers[r.Method] = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = c.CreateAccount(ctx, models.Payload{AccountID: "acc123"})
+	assert.NoError(t, err)
+	_, err = c.UpdateAccount(ctx, models.Payload{AccountID: "acc123"})
+	assert.NoError(t, err)
+	_, err = c.GetAccount(ctx, "acc123")
+	assert.NoError(t, err)
+
+	for _, method := range []string{http.MethodPost, http.MethodPut} {
+		assert.Equal(t, "application/json", headers[method].Get("Content-Type"), method)
+		assert.Equal(t, "application/json", headers[method].Get("Accept"), method)
+	}
+	assert.Empty(t, headers[http.MethodGet].Values("Content-Type"))
+	assert.Equal(t, "application/json", headers[http.MethodGet].Get("Accept"))
+}
+
+func TestIsReservedHeader(t *testing.T) {
+	for _, name := range []string{"X-Api-Key", "x-api-key", "Accept", "Content-Type", "AUTHORIZATION", "user-agent", client.IdempotencyKeyHeader, "x-api-version"} {
+		assert.True(t, client.IsReservedHeader(name), name)
+	}
+	assert.False(t, client.IsReservedHeader("X-Tenant-ID"))
+}
+
+func TestClient_WithToken(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get(AUTH_HEADER))
+		_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "provider-token")
+	assert.NoError(t, err)
+	c.RetryBudget = client.NewRetryBudget(1)
+	override := c.WithToken("override-token")
+
+	assert.Same(t, c.HTTPClient, override.HTTPClient)
+	assert.Same(t, c.Limiter, override.Limiter)
+	assert.Same(t, c.RetryBudget, override.RetryBudget)
+
+	_, err = override.GetAccount(context.Background(), "acc123")
+	assert.NoError(t, err)
+	_, err = c.GetAccount(context.Background(), "acc123")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"override-token", "provider-token"}, tokens)
+}
+
+func TestClient_UserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+	c.UserAgent = client.UserAgentPrefix + "/1.2.3"
+
+	err = c.Validate(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "terraform-provider-zesty/1.2.3", userAgent)
+}
+
+func TestClient_Validate_Unauthorized(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "revoked-token")
+	c.ValidateBackoff = time.Millisecond
+
+	err := c.Validate(context.Background())
+
+	var validationErr *client.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.True(t, client.IsUnauthorized(err))
+	assert.False(t, client.IsTemporary(err))
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, "validation failed: GET /validate: status: 403, body: ", err.Error())
+}
+
+func TestClient_Validate_APIVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		version       string
+		status        int
+		expectedError string
+	}{
+		{name: "matching version", version: "v2", status: http.StatusOK},
+		{name: "matching minor version", version: "v2.3", status: http.StatusOK},
+		{name: "unreported version", status: http.StatusOK},
+		{
+			name:          "mismatching version",
+			version:       "v1",
+			status:        http.StatusOK,
+			expectedError: "validation failed: the Zesty API reports version v1, but the provider requires version v2, check that the host points at the v2 endpoint",
+		},
+		{
+			name:          "mismatching version rejecting the request",
+			version:       "v1",
+			status:        http.StatusBadRequest,
+			expectedError: "validation failed: the Zesty API reports version v1, but the provider requires version v2, check that the host points at the v2 endpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requested = r.Header.Get(client.APIVersionHeader)
+				if tt.version != "" {
+					w.Header().Set(client.APIVersionHeader, tt.version)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			c, _ := client.NewClient(&server.URL, "testtoken")
+			err := c.Validate(context.Background())
+
+			assert.Equal(t, models.APIVersion, requested)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+			assert.True(t, client.IsVersionMismatch(err))
+			assert.False(t, client.IsTemporary(err))
+		})
+	}
+}
+
+func TestClient_Validate_RetriesTransientErrors(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	c.ValidateBackoff = time.Millisecond
+
+	assert.NoError(t, c.Validate(context.Background()))
+	assert.Equal(t, 3, requests)
+}
+
+func TestClient_Validate_ContextCanceledDuringRetry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	c.ValidateBackoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := c.Validate(ctx)
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, client.IsTemporary(err))
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, "validation failed: context canceled while waiting to retry after: GET /validate: status: 503, body: ", err.Error())
+}
+
+func TestClient_Validate_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	host := server.URL
+	server.Close()
+
+	c, _ := client.NewClient(&host, "testtoken")
+	c.ValidateBackoff = time.Millisecond
+
+	err := c.Validate(context.Background())
+
+	var validationErr *client.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, client.DefaultValidateAttempts, validationErr.Attempts)
+	assert.True(t, client.IsTemporary(err))
+	assert.False(t, client.IsUnauthorized(err))
+	assert.Contains(t, err.Error(), "validation failed after 3 attempts")
+}
+
+func TestClient_DoRequest_MaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		chunk := bytes.Repeat([]byte("<html>"), 1024)
+		for range 64 {
+			_, _ = w.Write(chunk)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	c.MaxResponseBytes = 64 * 1024
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/accounts", nil)
+	body, statusCode, err := c.DoRequest(req)
+	assert.Nil(t, body)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.EqualError(t, err, "response body from GET /accounts exceeds the limit of 65536 bytes, check that the host points at the Zesty API")
+
+	c.MaxResponseBytes = 6 * 64 * 1024
+	body, _, err = c.DoRequest(req)
+	assert.NoError(t, err)
+	assert.Len(t, body, 6*64*1024)
+}
+
+func TestClient_DoRequest_AcceptableStatusCodes(t *testing.T) {
+	var status atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	c.AcceptableStatusCodes = []int{http.StatusOK, http.StatusMultiStatus}
+
+	for _, code := range []int{http.StatusOK, http.StatusMultiStatus} {
+		status.Store(int32(code))
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/account", nil)
+		body, statusCode, err := c.DoRequest(req)
+		assert.NoError(t, err, code)
+		assert.Equal(t, code, statusCode)
+		assert.Equal(t, []byte(`{"accountID":"acc123"}`), body)
+	}
+
+	status.Store(http.StatusCreated)
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/account", nil)
+	body, statusCode, err := c.DoRequest(req)
+	assert.Nil(t, body)
+	assert.Equal(t, http.StatusCreated, statusCode)
+	var apiErr *client.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.EqualError(t, err, `POST /account: status: 201, body: {"accountID":"acc123"}`)
+
+	status.Store(http.StatusMultiStatus)
+	account, err := c.GetAccount(context.Background(), "acc123")
+	assert.NoError(t, err)
+	assert.Equal(t, "acc123", account.AccountID)
+}
+
+func TestClient_TrailingSlashHost(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	host := server.URL + "/kompass-platform//"
+	c, err := client.NewClient(&host, "testtoken")
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/kompass-platform", c.HostURL)
+
+	ctx := context.Background()
+	assert.NoError(t, c.Validate(ctx))
+	_, err = c.GetAccount(ctx, "acc123")
+	assert.NoError(t, err)
+	_, err = c.GetAccounts(ctx, client.AccountsFilter{})
+	assert.NoError(t, err)
+	_, err = c.CreateAccount(ctx, models.Payload{AccountID: "acc123"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/kompass-platform/validate",
+		"/kompass-platform/account",
+		"/kompass-platform/accounts",
+		"/kompass-platform/account",
+	}, paths)
+}
+
+func TestClient_UpdateAccount_Accepted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	account, err := c.UpdateAccount(context.Background(), models.Payload{AccountID: "acc123"})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Account{}, account)
+}
+
+func TestClient_UpdateAccount_SendsInactiveProducts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), `"CM":{"active":false}`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	_, err := c.UpdateAccount(context.Background(), models.Payload{
+		AccountID: "acc123",
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {Active: true},
+			models.CM:      {Active: false},
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func TestClient_UpdateAccountPartial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/account", r.URL.Path)
+		assert.Equal(t, client.MergePatchContentType, r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"accountID": "acc123",
+			"cloudProvider": "AWS",
+			"products": {"CM": {"active": false}},
+			"tags": {"team": null, "env": "prod"}
+		}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"accountID":"acc123","cloudProvider":"AWS","storageClassName":"managed-by-api"}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	prior := models.Payload{
+		AccountID:        "acc123",
+		CloudProvider:    models.AWS,
+		RoleARN:          "arn:aws:iam::123456789012:role/ZestyIamRole",
+		ExternalID:       "external-id",
+		StorageClassName: "gp3",
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {Active: true},
+			models.CM:      {Active: true},
+		},
+		Tags: map[string]string{"team": "platform"},
+	}
+	planned := prior
+	planned.Products = map[models.Product]models.ProductDetails{
+		models.Kompass: {Active: true},
+		models.CM:      {Active: false},
+	}
+	planned.Tags = map[string]string{"env": "prod"}
+
+	account, err := c.UpdateAccountPartial(context.Background(), prior, planned)
+	assert.NoError(t, err)
+	assert.Equal(t, "managed-by-api", account.StorageClassName)
+}
+
+func TestClient_SetProductActive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/account", r.URL.Path)
+		assert.Equal(t, client.MergePatchContentType, r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"accountID": "acc123", "products": {"CM": {"active": false}}}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"accountID":"acc123","products":{"Kompass":{"active":true},"CM":{"active":false}}}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	account, err := c.SetProductActive(context.Background(), "acc123", models.CM, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "acc123", account.AccountID)
+	assert.Equal(t, map[models.Product]models.ProductDetails{
+		models.Kompass: {Active: true},
+		models.CM:      {Active: false},
+	}, account.Products)
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+	c.Limiter = rate.NewLimiter(rate.Limit(10), 1)
+
+	for range 3 {
+		assert.NoError(t, c.Validate(context.Background()))
+	}
+
+	assert.Len(t, requests, 3)
+	for i := 1; i < len(requests); i++ {
+		assert.GreaterOrEqual(t, requests[i].Sub(requests[i-1]), 90*time.Millisecond)
+	}
+}
+
+func TestClient_RateLimit_ContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+	c.Limiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+
+	assert.NoError(t, c.Validate(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, c.Validate(ctx))
+}
+
+func TestClient_GetAccountHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/"+models.APIVersion+"/account/history", r.URL.Path)
+		assert.Equal(t, "acc123", r.URL.Query().Get("accountID"))
+		_, _ = w.Write([]byte(`[
+			{"timestamp": "2024-03-01T12:00:00Z", "products": {"Kompass": {"active": true, "values": {"threshold": 90}}}},
+			{"timestamp": "2024-01-01T12:00:00Z", "products": {"Kompass": {"active": true, "values": {"threshold": 80}}}}
+		]`))
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+
+	snapshots, err := c.GetAccountHistory(context.Background(), "acc123")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AccountSnapshot{
+		{
+			Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			Products:  map[models.Product]models.ProductDetails{models.Kompass: {Active: true, Values: map[string]any{"threshold": float64(80)}}},
+		},
+		{
+			Timestamp: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			Products:  map[models.Product]models.ProductDetails{models.Kompass: {Active: true, Values: map[string]any{"threshold": float64(90)}}},
+		},
+	}, snapshots)
+}
+
+func TestClient_GetAccountHistory_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+
+	snapshots, err := c.GetAccountHistory(context.Background(), "acc123")
+	assert.True(t, client.IsNotFound(err))
+	assert.Nil(t, snapshots)
+}
+
+func TestClient_AccountEnvelope(t *testing.T) {
+	bodies := map[string]string{
+		"bare":      `{"AccountID": "acc123", "CloudProvider": "AWS", "organizationID": 7}`,
+		"enveloped": `{"data": {"AccountID": "acc123", "CloudProvider": "AWS", "organizationID": 7}}`,
+	}
+
+	for shape, body := range bodies {
+		t.Run(shape, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			c, err := client.NewClient(&server.URL, "testtoken")
+			assert.NoError(t, err)
+			ctx := context.Background()
+			payload := models.Payload{AccountID: "acc123", CloudProvider: models.AWS}
+
+			calls := map[string]func() (*models.Account, error){
+				"create":         func() (*models.Account, error) { return c.CreateAccount(ctx, payload) },
+				"get":            func() (*models.Account, error) { return c.GetAccount(ctx, "acc123") },
+				"update":         func() (*models.Account, error) { return c.UpdateAccount(ctx, payload) },
+				"partial update": func() (*models.Account, error) { return c.UpdateAccountPartial(ctx, payload, payload) },
+			}
+			for name, call := range calls {
+				account, err := call()
+				assert.NoError(t, err, name)
+				if assert.NotNil(t, account, name) {
+					assert.Equal(t, "acc123", account.AccountID, name)
+					assert.Equal(t, models.AWS, account.CloudProvider, name)
+					assert.Equal(t, int64(7), account.OrganizationID, name)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_NumericAccountID(t *testing.T) {
+	bodies := map[string]string{
+		"string":            `{"accountID": "123456789012", "cloudProvider": "AWS"}`,
+		"number":            `{"accountID": 123456789012, "cloudProvider": "AWS"}`,
+		"enveloped number":  `{"data": {"accountID": 123456789012, "cloudProvider": "AWS"}}`,
+		"list of numbers":   `[{"accountID": 123456789012, "cloudProvider": "AWS"}]`,
+		"list of mixed IDs": `[{"accountID": "123456789012", "cloudProvider": "AWS"}, {"accountID": 210987654321, "cloudProvider": "AWS"}]`,
+	}
+
+	for shape, body := range bodies {
+		t.Run(shape, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			c, err := client.NewClient(&server.URL, "testtoken")
+			assert.NoError(t, err)
+			c.StrictDecoding = true
+
+			if strings.HasPrefix(body, "[") {
+				accounts, err := c.GetAccounts(context.Background(), client.AccountsFilter{})
+				assert.NoError(t, err)
+				if assert.NotEmpty(t, *accounts) {
+					assert.Equal(t, "123456789012", (*accounts)[0].AccountID)
+					assert.Equal(t, models.AWS, (*accounts)[0].CloudProvider)
+				}
+				return
+			}
+
+			account, err := c.GetAccount(context.Background(), "123456789012")
+			assert.NoError(t, err)
+			if assert.NotNil(t, account) {
+				assert.Equal(t, "123456789012", account.AccountID)
+				assert.Equal(t, models.AWS, account.CloudProvider)
+			}
+		})
+	}
+}
+
+func TestClient_AccountEnvelope_Empty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+
+	account, err := c.CreateAccount(context.Background(), models.Payload{AccountID: "acc123"})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Account{}, account)
+}
+
+func TestClient_BasePath(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/accounts"), strings.HasSuffix(r.URL.Path, "/history"):
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+		}
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+	c.BasePath = client.NormalizeBasePath("zesty/v2/")
+	c.DeletePollInterval = time.Millisecond
+	ctx := context.Background()
+	payload := models.Payload{AccountID: "acc123"}
+
+	assert.NoError(t, c.Validate(ctx))
+	_, err = c.CreateAccount(ctx, payload)
+	assert.NoError(t, err)
+	_, err = c.GetAccount(ctx, "acc123")
+	assert.NoError(t, err)
+	_, err = c.GetAccountInOrg(ctx, 42, "acc123")
+	assert.NoError(t, err)
+	_, err = c.GetAccounts(ctx, client.AccountsFilter{})
+	assert.NoError(t, err)
+	_, err = c.GetAccountHistory(ctx, "acc123")
+	assert.NoError(t, err)
+	_, err = c.UpdateAccount(ctx, payload)
+	assert.NoError(t, err)
+	_, err = c.UpdateAccountPartial(ctx, payload, payload)
+	assert.NoError(t, err)
+	assert.NoError(t, c.DeleteAccount(ctx, payload))
+
+	assert.Equal(t, []string{
+		"GET /zesty/v2/validate",
+		"POST /zesty/v2/account",
+		"GET /zesty/v2/account",
+		"GET /zesty/v2/account",
+		"GET /zesty/v2/accounts",
+		"GET /zesty/v2/" + models.APIVersion + "/account/history",
+		"PUT /zesty/v2/account",
+		"PATCH /zesty/v2/account",
+		"DELETE /zesty/v2/account",
+	}, paths)
+}
+
+func TestNormalizeBasePath(t *testing.T) {
+	tests := map[string]string{
+		"":              "",
+		"/":             "",
+		" // ":          "",
+		"zesty":         "/zesty",
+		"/zesty/v2":     "/zesty/v2",
+		"zesty/v2/":     "/zesty/v2",
+		"//zesty//v2//": "/zesty/v2",
+	}
+
+	for basePath, expected := range tests {
+		assert.Equal(t, expected, client.NormalizeBasePath(basePath), basePath)
+	}
+}
+
+func TestClient_GetAccountInOrg(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/account", r.URL.Path)
+		assert.Equal(t, "acc123", r.URL.Query().Get("accountID"))
+		assert.Equal(t, "42", r.URL.Query().Get("organizationID"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"AccountID":"acc123","OrganizationID":42}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	account, err := c.GetAccountInOrg(context.Background(), 42, "acc123")
+	assert.NoError(t, err)
+	assert.Equal(t, "acc123", account.AccountID)
+	assert.Equal(t, int64(42), account.OrganizationID)
+}
+
+func TestClient_GetAccount_NoOrganization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "acc 123", r.URL.Query().Get("accountID"))
+		assert.False(t, r.URL.Query().Has("organizationID"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"AccountID":"acc 123"}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	_, err := c.GetAccount(context.Background(), "acc 123")
+	assert.NoError(t, err)
+}
+
+func TestClient_NonJSONBody(t *testing.T) {
+	html := `<!DOCTYPE html><html><head><title>502 Bad Gateway</title></head><body>` + strings.Repeat("<p>upstream unavailable</p>", 40) + `</body></html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/validate" {
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(html))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"create": func() error {
+			_, err := c.CreateAccount(ctx, models.Payload{AccountID: "acc123"})
+			return err
+		},
+		"update": func() error {
+			_, err := c.UpdateAccount(ctx, models.Payload{AccountID: "acc123"})
+			return err
+		},
+		"get": func() error {
+			_, err := c.GetAccount(ctx, "acc123")
+			return err
+		},
+		"list": func() error {
+			_, err := c.GetAccounts(ctx, client.AccountsFilter{})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call()
+
+			var decodeErr *client.DecodeError
+			if assert.ErrorAs(t, err, &decodeErr) {
+				assert.Equal(t, http.StatusOK, decodeErr.StatusCode)
+			}
+			assert.Contains(t, err.Error(), "unexpected non-JSON response")
+			assert.Contains(t, err.Error(), "status: 200")
+			assert.Contains(t, err.Error(), "<title>502 Bad Gateway</title>")
+			assert.Contains(t, err.Error(), "... (truncated)")
+			assert.NotContains(t, err.Error(), "</html>")
+			assert.NotContains(t, err.Error(), "invalid character")
+		})
+	}
+}
+
+func TestClient_MismatchedJSONBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`["not", "an", "account"]`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+
+	_, err := c.GetAccount(context.Background(), "acc123")
+	var decodeErr *client.DecodeError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &decodeErr))
+}
+
+func TestClient_StrictDecoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		strict   bool
+		body     string
+		id       string
+		expected []string
+	}{
+		{
+			name:   "disabled",
+			strict: false,
+			body:   `{"accountID": "acc123", "cloudProvidr": "AWS"}`,
+		},
+		{
+			name:     "unexpected field",
+			strict:   true,
+			body:     `{"accountID": "acc123", "cloudProvidr": "AWS"}`,
+			expected: []string{"cloudProvidr"},
+		},
+		{
+			name:     "unexpected fields",
+			strict:   true,
+			body:     `{"accountID": "acc123", "regoin": "us-east-1", "cloudProvidr": "AWS"}`,
+			expected: []string{"cloudProvidr", "regoin"},
+		},
+		{
+			name:     "unexpected nested field",
+			strict:   true,
+			body:     `{"accountID": "acc123", "products": {"Kompass": {"active": true, "regoin": "us-east-1"}}}`,
+			expected: []string{"products.Kompass.regoin"},
+		},
+		{
+			name:     "unexpected enveloped field",
+			strict:   true,
+			body:     `{"data": {"accountID": "acc123", "cloudProvidr": "AWS"}}`,
+			expected: []string{"data.cloudProvidr"},
+		},
+		{
+			name:   "field in another case",
+			strict: true,
+			body:   `{"accountID": "acc123", "CloudProvider": "AWS"}`,
+		},
+		{
+			name:   "known fields",
+			strict: true,
+			body:   `{"accountID": "acc123", "cloudProvider": "AWS"}`,
+		},
+		{
+			name:   "envelope",
+			strict: true,
+			body:   `{"data": {"accountID": "acc123", "cloudProvider": "AWS"}}`,
+		},
+		{
+			name:   "numeric account ID",
+			strict: true,
+			body:   `{"accountID": 123, "cloudProvider": "AWS"}`,
+			id:     "123",
+		},
+		{
+			name:     "unexpected field after numeric account ID",
+			strict:   true,
+			body:     `{"accountID": 123, "cloudProvidr": "AWS"}`,
+			id:       "123",
+			expected: []string{"cloudProvidr"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			c, err := client.NewClient(&server.URL, "testtoken")
+			assert.NoError(t, err)
+			c.StrictDecoding = tt.strict
+
+			var output bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &output)
+			id := tt.id
+			if id == "" {
+				id = "acc123"
+			}
+			account, err := c.GetAccount(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, id, account.AccountID)
+
+			entries, err := tflogtest.MultilineJSONDecode(&output)
+			assert.NoError(t, err)
+			var fields []string
+			for _, entry := range entries {
+				if entry["@message"] == "Zesty API response has unexpected fields" {
+					assert.Equal(t, "warn", entry["@level"])
+					for _, field := range entry["fields"].([]any) {
+						fields = append(fields, field.(string))
+					}
+				}
+			}
+			assert.Equal(t, tt.expected, fields)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxBodyPreview is the number of bytes of a response body included in error messages.
+const maxBodyPreview = 512
+
+// APIError is returned when the Zesty API responds with an unsuccessful status code.
+type APIError struct {
+	// Method and Path identify the failed request. The host and query are left out, as the query
+	// may hold secrets.
+	Method     string
+	Path       string
+	StatusCode int
+	Body       []byte
+	// RequestID is the ID the API or a gateway assigned to the request in one of RequestIDHeaders,
+	// for reference when reporting the failure to Zesty support. It is empty when none was sent.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("status: %d, body: %s", e.StatusCode, bodyPreview(e.Body))
+	if e.RequestID != "" {
+		message = fmt.Sprintf("status: %d, request ID: %s, body: %s", e.StatusCode, e.RequestID, bodyPreview(e.Body))
+	}
+	if e.Method == "" {
+		return message
+	}
+	return fmt.Sprintf("%s %s: %s", e.Method, e.Path, message)
+}
+
+// DecodeError is returned when a successful response body is not JSON, for example an HTML page
+// served by a gateway or proxy in front of the API.
+type DecodeError struct {
+	StatusCode int
+	Body       []byte
+	Err        error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("unexpected non-JSON response, check that the host points at the Zesty API (status: %d, body: %s)", e.StatusCode, bodyPreview(e.Body))
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// VersionMismatchError is returned when the API reports a version incompatible with
+// models.APIVersion, usually because the host points at an endpoint of another version. APIs that
+// do not report their version are assumed to be compatible.
+type VersionMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("the Zesty API reports version %s, but the provider requires version %s, check that the host points at the %s endpoint", e.Actual, e.Expected, e.Expected)
+}
+
+// bodyPreview returns body for use in error messages, truncated to maxBodyPreview bytes.
+func bodyPreview(body []byte) string {
+	if len(body) <= maxBodyPreview {
+		return string(body)
+	}
+	return strings.ToValidUTF8(string(body[:maxBodyPreview]), "") + "... (truncated)"
+}
+
+// IsNotFound reports whether err is an APIError for a 404 response.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsVersionMismatch reports whether err is a VersionMismatchError.
+func IsVersionMismatch(err error) bool {
+	var versionErr *VersionMismatchError
+	return errors.As(err, &versionErr)
+}
+
+// IsUnauthorized reports whether err is an APIError for a 401 or 403 response, meaning the API
+// rejected the token.
+func IsUnauthorized(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// IsTemporary reports whether err is likely to go away when the request is retried: a timeout, a
+// failure to connect or a dropped connection, or an APIError for a 429 or 5xx response. TLS and
+// other configuration errors are not temporary.
+func IsTemporary(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+	var opErr *net.OpError
+	return urlErr.Timeout() || errors.As(urlErr.Err, &opErr) || errors.Is(urlErr.Err, io.EOF) || errors.Is(urlErr.Err, io.ErrUnexpectedEOF)
+}
+
+// ValidationError is returned by Validate. Use IsUnauthorized and IsTemporary on it to tell a
+// rejected token apart from an API that could not be reached.
+type ValidationError struct {
+	// Attempts is the number of requests sent before giving up.
+	Attempts int
+	Err      error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("validation failed after %d attempts: %s", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("validation failed: %s", e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,160 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+)
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "not found",
+			err:      &client.APIError{StatusCode: http.StatusNotFound},
+			expected: true,
+		},
+		{
+			name:     "wrapped not found",
+			err:      fmt.Errorf("reading savings: %w", &client.APIError{StatusCode: http.StatusNotFound}),
+			expected: true,
+		},
+		{
+			name:     "other status",
+			err:      &client.APIError{StatusCode: http.StatusForbidden},
+			expected: false,
+		},
+		{
+			name:     "non API error",
+			err:      errors.New("connection refused"),
+			expected: false,
+		},
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, client.IsNotFound(tt.err))
+		})
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	assert.True(t, client.IsUnauthorized(&client.APIError{StatusCode: http.StatusUnauthorized}))
+	assert.True(t, client.IsUnauthorized(&client.ValidationError{Err: &client.APIError{StatusCode: http.StatusForbidden}}))
+	assert.False(t, client.IsUnauthorized(&client.APIError{StatusCode: http.StatusServiceUnavailable}))
+	assert.False(t, client.IsUnauthorized(errors.New("connection refused")))
+	assert.False(t, client.IsUnauthorized(nil))
+}
+
+func TestIsVersionMismatch(t *testing.T) {
+	assert.True(t, client.IsVersionMismatch(&client.VersionMismatchError{Expected: "v2", Actual: "v1"}))
+	assert.True(t, client.IsVersionMismatch(&client.ValidationError{Err: &client.VersionMismatchError{Expected: "v2", Actual: "v1"}}))
+	assert.False(t, client.IsVersionMismatch(&client.APIError{StatusCode: http.StatusBadRequest}))
+	assert.False(t, client.IsVersionMismatch(nil))
+}
+
+func TestIsTemporary(t *testing.T) {
+	assert.True(t, client.IsTemporary(&client.APIError{StatusCode: http.StatusServiceUnavailable}))
+	assert.True(t, client.IsTemporary(&client.APIError{StatusCode: http.StatusTooManyRequests}))
+	assert.True(t, client.IsTemporary(&url.Error{Op: "Get", URL: "http://127.0.0.1:1", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}))
+	assert.True(t, client.IsTemporary(&url.Error{Op: "Get", URL: "http://127.0.0.1:1", Err: io.EOF}))
+	assert.False(t, client.IsTemporary(&url.Error{Op: "Get", URL: "https://127.0.0.1:1", Err: errors.New("tls: failed to verify certificate")}))
+	assert.False(t, client.IsTemporary(&client.APIError{StatusCode: http.StatusForbidden}))
+	assert.False(t, client.IsTemporary(errors.New("invalid character")))
+	assert.False(t, client.IsTemporary(nil))
+}
+
+func TestClient_DoRequest_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "token")
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/savings?secret=s3cr3t", nil)
+	_, _, err := c.DoRequest(req)
+
+	assert.True(t, client.IsNotFound(err))
+	assert.Contains(t, err.Error(), "status: 404")
+	assert.True(t, strings.HasPrefix(err.Error(), "GET /savings: status: 404, body: "), err.Error())
+	assert.NotContains(t, err.Error(), "s3cr3t")
+	assert.NotContains(t, err.Error(), server.URL)
+}
+
+func TestClient_DoRequest_RequestID(t *testing.T) {
+	tests := map[string]string{
+		"X-Request-ID":     "req-123",
+		"X-Correlation-ID": "corr-456",
+	}
+
+	for header, id := range tests {
+		t.Run(header, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(header, id)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			c, _ := client.NewClient(&server.URL, "token")
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/account", nil)
+			_, _, err := c.DoRequest(req)
+
+			var apiErr *client.APIError
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, id, apiErr.RequestID)
+			assert.Contains(t, err.Error(), "request ID: "+id)
+		})
+	}
+}
+
+func TestAPIError_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *client.APIError
+		expected string
+	}{
+		{
+			name:     "without request",
+			err:      &client.APIError{StatusCode: http.StatusNotFound, Body: []byte("not found")},
+			expected: "status: 404, body: not found",
+		},
+		{
+			name:     "with request",
+			err:      &client.APIError{Method: http.MethodPut, Path: "/accounts/123", StatusCode: http.StatusConflict, Body: []byte(`{"message":"conflict"}`)},
+			expected: `PUT /accounts/123: status: 409, body: {"message":"conflict"}`,
+		},
+		{
+			name:     "with request ID",
+			err:      &client.APIError{Method: http.MethodGet, Path: "/account", StatusCode: http.StatusInternalServerError, Body: []byte("oops"), RequestID: "req-123"},
+			expected: "GET /account: status: 500, request ID: req-123, body: oops",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.err.Error())
+		})
+	}
+}
+
+func TestAPIError_LongBody(t *testing.T) {
+	err := &client.APIError{StatusCode: http.StatusBadGateway, Body: []byte(strings.Repeat("x", 2000))}
+
+	assert.Less(t, len(err.Error()), 600)
+	assert.True(t, strings.HasSuffix(err.Error(), "... (truncated)"))
+}
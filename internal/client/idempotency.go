@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// IdempotencyKeyHeader carries the key the API uses to deduplicate retried create requests.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context that makes CreateAccount send key, such as a UUID chosen by
+// the caller, instead of the key derived from the payload.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKey returns the key set with WithIdempotencyKey, falling back to a SHA-256 hash of
+// the request body so identical payloads share a key.
+func idempotencyKey(ctx context.Context, body []byte) string {
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		return key
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
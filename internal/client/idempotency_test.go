@@ -0,0 +1,40 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+func TestClient_CreateAccount_IdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(client.IdempotencyKeyHeader))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	payload := models.Payload{AccountID: "acc123", CloudProvider: models.AWS}
+
+	_, err := c.CreateAccount(context.Background(), payload)
+	assert.NoError(t, err)
+	_, err = c.CreateAccount(context.Background(), payload)
+	assert.NoError(t, err)
+	payload.AccountID = "acc456"
+	_, err = c.CreateAccount(context.Background(), payload)
+	assert.NoError(t, err)
+	_, err = c.CreateAccount(client.WithIdempotencyKey(context.Background(), "6f1c2a9e-caller-key"), payload)
+	assert.NoError(t, err)
+
+	assert.Len(t, keys, 4)
+	assert.Len(t, keys[0], 64)
+	assert.Equal(t, keys[0], keys[1])
+	assert.NotEqual(t, keys[0], keys[2])
+	assert.Equal(t, "6f1c2a9e-caller-key", keys[3])
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// logRetry warns that a request is about to be retried after backoff, so slow applies can be
+// explained from the logs. The status code is logged when err is an APIError.
+func logRetry(ctx context.Context, message string, attempt int, err error, backoff time.Duration) {
+	fields := map[string]any{
+		"attempt": attempt,
+		"backoff": backoff.String(),
+		"error":   err.Error(),
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		fields["status_code"] = apiErr.StatusCode
+	}
+	tflog.Warn(ctx, message, fields)
+}
+
+// redacted replaces sensitive values in logged request and response bodies.
+const redacted = "***"
+
+// sensitiveKeys lists the JSON keys, compared case-insensitively, whose values are never logged.
+var sensitiveKeys = map[string]bool{
+	"apikey":     true,
+	"externalid": true,
+	"password":   true,
+	"secret":     true,
+	"token":      true,
+	"x-api-key":  true,
+}
+
+// redactBody returns a loggable representation of a JSON body with sensitive values masked.
+// Bodies that are not JSON are omitted entirely, as their contents cannot be inspected.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "<non-JSON body omitted>"
+	}
+
+	redactedBody, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return "<body omitted>"
+	}
+
+	return string(redactedBody)
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if sensitiveKeys[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// requestBody reads a copy of the request body for logging without consuming it.
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+
+	return b
+}
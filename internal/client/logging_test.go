@@ -0,0 +1,174 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+func TestClient_DebugLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"accountID":"123456789012","additionalData":{"externalID":"secret-external-id","token":"secret-token"}}`))
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "secret-token")
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	_, err = c.CreateAccount(ctx, models.Payload{
+		AccountID:  "123456789012",
+		ExternalID: "secret-external-id",
+	})
+	assert.NoError(t, err)
+
+	logs := output.String()
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	assert.Equal(t, "Sending Zesty API request", entries[0]["@message"])
+	assert.Equal(t, "POST", entries[0]["method"])
+	assert.Equal(t, "/account", entries[0]["path"])
+
+	assert.Equal(t, "Received Zesty API response", entries[1]["@message"])
+	assert.Equal(t, float64(http.StatusCreated), entries[1]["status_code"])
+	assert.Contains(t, entries[1], "duration_ms")
+
+	assert.Equal(t, "Created Zesty account", entries[2]["@message"])
+	assert.Equal(t, true, entries[2]["created"])
+
+	assert.NotContains(t, logs, "secret-token")
+	assert.NotContains(t, logs, "secret-external-id")
+	assert.Contains(t, logs, "123456789012")
+}
+
+func TestClient_LogLevel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		level    hclog.Level
+		expected []string
+	}{
+		{
+			name:     "inherited",
+			level:    hclog.NoLevel,
+			expected: []string{"Sending Zesty API request", "Received Zesty API response"},
+		},
+		{
+			name:     "debug",
+			level:    hclog.Debug,
+			expected: []string{"Sending Zesty API request", "Received Zesty API response"},
+		},
+		{
+			name:  "warn",
+			level: hclog.Warn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := client.NewClient(&server.URL, "testtoken")
+			assert.NoError(t, err)
+			c.LogLevel = tt.level
+
+			var output bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &output)
+			assert.NoError(t, c.Validate(ctx))
+
+			entries, err := tflogtest.MultilineJSONDecode(&output)
+			assert.NoError(t, err)
+			var messages []string
+			for _, entry := range entries {
+				assert.Equal(t, "provider."+client.LogSubsystem, entry["@module"])
+				messages = append(messages, entry["@message"].(string))
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}
+
+func TestClient_RetryLogging(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+	c.ValidateBackoff = time.Millisecond
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	assert.NoError(t, c.Validate(ctx))
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	assert.NoError(t, err)
+
+	var retries []map[string]any
+	for _, entry := range entries {
+		if entry["@message"] == "Retrying Zesty API validation" {
+			retries = append(retries, entry)
+		}
+	}
+	if assert.Len(t, retries, 2) {
+		for i, entry := range retries {
+			assert.Equal(t, "warn", entry["@level"])
+			assert.Equal(t, float64(i+1), entry["attempt"])
+			assert.Equal(t, float64(http.StatusServiceUnavailable), entry["status_code"])
+			assert.Contains(t, entry["error"], "status: 503")
+			assert.Contains(t, entry, "backoff")
+		}
+	}
+}
+
+func TestClient_RetryLogging_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	host := server.URL
+	server.Close()
+
+	c, err := client.NewClient(&host, "testtoken")
+	assert.NoError(t, err)
+	c.ValidateAttempts = 2
+	c.ValidateBackoff = time.Millisecond
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	assert.Error(t, c.Validate(ctx))
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		if entry["@message"] == "Retrying Zesty API validation" {
+			assert.Equal(t, float64(1), entry["attempt"])
+			assert.NotContains(t, entry, "status_code")
+			assert.Contains(t, entry["error"], "connection refused")
+			return
+		}
+	}
+	t.Fatal("expected the retry to be logged")
+}
@@ -0,0 +1,271 @@
+package client
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+	"golang.org/x/time/rate"
+)
+
+// Option configures a client built by NewClientWithOptions.
+type Option func(*clientOptions) error
+
+// clientOptions collects the options of NewClientWithOptions, so they apply in any order.
+type clientOptions struct {
+	host             string
+	basePath         string
+	accountIDParam   string
+	token            string
+	userAgent        string
+	timeout          time.Duration
+	validateAttempts int
+	retryConfig      RetryConfig
+	retryBudget      *RetryBudget
+	httpClient       *http.Client
+	limit            rate.Limit
+	readOnly         bool
+	extraHeaders     map[string]string
+	logLevel         hclog.Level
+	strictDecoding   bool
+}
+
+// WithHost points the client at the API at host instead of models.DefaultHostURL. The host is
+// checked with NormalizeHostURL.
+func WithHost(host string) Option {
+	return func(o *clientOptions) error {
+		hostURL, err := NormalizeHostURL(host)
+		if err != nil {
+			return err
+		}
+		o.host = hostURL
+		return nil
+	}
+}
+
+// WithBasePath prepends basePath, normalized by NormalizeBasePath, to the path of every endpoint.
+func WithBasePath(basePath string) Option {
+	return func(o *clientOptions) error {
+		o.basePath = NormalizeBasePath(basePath)
+		return nil
+	}
+}
+
+// WithAccountIDParam sets the name of the query parameter carrying the account ID of lookups, instead
+// of DefaultAccountIDParam.
+func WithAccountIDParam(name string) Option {
+	return func(o *clientOptions) error {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("account ID parameter name must not be empty")
+		}
+		o.accountIDParam = name
+		return nil
+	}
+}
+
+// WithAuthHeader sets the token sent in the X-Api-Key header of every request.
+func WithAuthHeader(token string) Option {
+	return func(o *clientOptions) error {
+		o.token = token
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request, instead of DefaultTimeout. It also overrides the
+// timeout of a client set with WithHTTPClient, without modifying that client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) error {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout must be positive, got %s", timeout)
+		}
+		o.timeout = timeout
+		return nil
+	}
+}
+
+// WithValidateAttempts sets the number of times Validate tries the API before giving up on
+// transient failures, instead of DefaultValidateAttempts. Other requests are retried as configured
+// by WithRetryConfig.
+func WithValidateAttempts(attempts int) Option {
+	return func(o *clientOptions) error {
+		if attempts < 1 {
+			return fmt.Errorf("attempts must be at least 1, got %d", attempts)
+		}
+		o.validateAttempts = attempts
+		return nil
+	}
+}
+
+// WithRetryConfig retries failed requests sent with DoRequest as configured by retry. WaitMin and
+// WaitMax default to DefaultRetryWaitMin and DefaultRetryWaitMax when zero. Without this option,
+// failed requests are not retried.
+func WithRetryConfig(retry RetryConfig) Option {
+	return func(o *clientOptions) error {
+		if retry.MaxAttempts < 1 {
+			return fmt.Errorf("max attempts must be at least 1, got %d", retry.MaxAttempts)
+		}
+		if retry.WaitMin < 0 || retry.WaitMax < 0 {
+			return fmt.Errorf("retry waits must not be negative, got %s and %s", retry.WaitMin, retry.WaitMax)
+		}
+		if retry.WaitMin == 0 {
+			retry.WaitMin = DefaultRetryWaitMin
+		}
+		if retry.WaitMax == 0 {
+			retry.WaitMax = max(retry.WaitMin, DefaultRetryWaitMax)
+		}
+		if retry.WaitMax < retry.WaitMin {
+			return fmt.Errorf("maximum retry wait %s must not be less than the minimum retry wait %s", retry.WaitMax, retry.WaitMin)
+		}
+		for _, statusCode := range retry.RetryOnStatus {
+			if statusCode < 100 || statusCode > 599 {
+				return fmt.Errorf("retry status codes must be between 100 and 599, got %d", statusCode)
+			}
+		}
+		o.retryConfig = retry
+		return nil
+	}
+}
+
+// WithRetryBudget caps the retries of all requests sent with the client, and with the copies made by
+// WithToken, to retries in total. Without this option, retries are unlimited.
+func WithRetryBudget(retries int64) Option {
+	return func(o *clientOptions) error {
+		if retries < 0 {
+			return fmt.Errorf("retry budget must not be negative, got %d", retries)
+		}
+		o.retryBudget = NewRetryBudget(retries)
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request, instead of UserAgentPrefix.
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) error {
+		if strings.TrimSpace(userAgent) == "" {
+			return fmt.Errorf("user agent must not be empty")
+		}
+		o.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithRateLimit throttles requests to limit requests per second. Without this option, requests are
+// not throttled.
+func WithRateLimit(limit rate.Limit) Option {
+	return func(o *clientOptions) error {
+		if limit <= 0 {
+			return fmt.Errorf("rate limit must be positive, got %v", float64(limit))
+		}
+		o.limit = limit
+		return nil
+	}
+}
+
+// WithReadOnly turns the mutating requests of the client into no-ops, as described by
+// Client.ReadOnly.
+func WithReadOnly(readOnly bool) Option {
+	return func(o *clientOptions) error {
+		o.readOnly = readOnly
+		return nil
+	}
+}
+
+// WithExtraHeaders sends headers with every request. ReservedHeaders are rejected, as the client sets
+// them itself.
+func WithExtraHeaders(headers map[string]string) Option {
+	return func(o *clientOptions) error {
+		for name := range headers {
+			if IsReservedHeader(name) {
+				return fmt.Errorf("header %s is set by the client and cannot be overridden", name)
+			}
+		}
+		o.extraHeaders = maps.Clone(headers)
+		return nil
+	}
+}
+
+// WithLogLevel sets the level of the LogSubsystem logger requests and responses are logged to,
+// instead of the level of the provider logger.
+func WithLogLevel(level hclog.Level) Option {
+	return func(o *clientOptions) error {
+		o.logLevel = level
+		return nil
+	}
+}
+
+// WithStrictDecoding warns about fields of API responses the client does not know, as described by
+// Client.StrictDecoding.
+func WithStrictDecoding(strict bool) Option {
+	return func(o *clientOptions) error {
+		o.strictDecoding = strict
+		return nil
+	}
+}
+
+// WithHTTPClient sends requests with a copy of httpClient instead of a client using a transport built
+// by NewTransport with the default connection pool settings. The copy keeps the timeout of httpClient
+// unless WithTimeout is also set.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) error {
+		if httpClient == nil {
+			return fmt.Errorf("HTTP client must not be nil")
+		}
+		o.httpClient = httpClient
+		return nil
+	}
+}
+
+// NewClientWithOptions returns a client configured by opts. Without options, it sends no token to
+// the API at models.DefaultHostURL, using the package defaults.
+func NewClientWithOptions(opts ...Option) (*Client, error) {
+	o := clientOptions{
+		host:             models.DefaultHostURL,
+		userAgent:        UserAgentPrefix,
+		validateAttempts: DefaultValidateAttempts,
+		limit:            rate.Inf,
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	var httpClient http.Client
+	if o.httpClient != nil {
+		httpClient = *o.httpClient
+	} else {
+		transport, err := NewTransport(TransportConfig{})
+		if err != nil {
+			return nil, err
+		}
+		httpClient = http.Client{Timeout: DefaultTimeout, Transport: transport}
+	}
+	if o.timeout != 0 {
+		httpClient.Timeout = o.timeout
+	}
+
+	return &Client{
+		HTTPClient:     &httpClient,
+		HostURL:        o.host,
+		BasePath:       o.basePath,
+		AccountIDParam: o.accountIDParam,
+		Token:          o.token,
+		UserAgent:      o.userAgent,
+		Limiter:        rate.NewLimiter(o.limit, 1),
+		ReadOnly:       o.readOnly,
+		ExtraHeaders:   o.extraHeaders,
+		LogLevel:       o.logLevel,
+		StrictDecoding: o.strictDecoding,
+
+		Retry:              o.retryConfig,
+		RetryBudget:        o.retryBudget,
+		ValidateAttempts:   o.validateAttempts,
+		ValidateBackoff:    DefaultValidateBackoff,
+		DeletePollInterval: DefaultDeletePollInterval,
+		MaxResponseBytes:   DefaultMaxResponseBytes,
+	}, nil
+}
@@ -0,0 +1,184 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+	"golang.org/x/time/rate"
+)
+
+func TestNewClientWithOptions_Defaults(t *testing.T) {
+	c, err := client.NewClientWithOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, models.DefaultHostURL, c.HostURL)
+	assert.Empty(t, c.Token)
+	assert.Empty(t, c.BasePath)
+	assert.Equal(t, client.DefaultTimeout, c.HTTPClient.Timeout)
+	assert.NotNil(t, c.HTTPClient.Transport)
+	assert.Equal(t, client.DefaultValidateAttempts, c.ValidateAttempts)
+	assert.Equal(t, client.DefaultValidateBackoff, c.ValidateBackoff)
+	assert.Equal(t, int64(client.DefaultMaxResponseBytes), c.MaxResponseBytes)
+	assert.Equal(t, client.UserAgentPrefix, c.UserAgent)
+	assert.NotNil(t, c.Limiter)
+	assert.Equal(t, rate.Inf, c.Limiter.Limit())
+	assert.Zero(t, c.Retry)
+	assert.Nil(t, c.RetryBudget)
+	assert.False(t, c.ReadOnly)
+	assert.Empty(t, c.ExtraHeaders)
+	assert.Equal(t, hclog.NoLevel, c.LogLevel)
+	assert.False(t, c.StrictDecoding)
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get(AUTH_HEADER)
+		assert.Equal(t, "/zesty/validate", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c, err := client.NewClientWithOptions(
+		client.WithHost(server.URL+"/"),
+		client.WithBasePath("/zesty/"),
+		client.WithAuthHeader("option-token"),
+		client.WithTimeout(5*time.Second),
+		client.WithValidateAttempts(5),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL, c.HostURL)
+	assert.Equal(t, "/zesty", c.BasePath)
+	assert.Equal(t, 5*time.Second, c.HTTPClient.Timeout)
+	assert.Equal(t, 5, c.ValidateAttempts)
+
+	assert.NoError(t, c.Validate(context.Background()))
+	assert.Equal(t, "option-token", token)
+}
+
+func TestNewClientWithOptions_Settings(t *testing.T) {
+	headers := map[string]string{"X-Tenant-ID": "tenant-1"}
+	c, err := client.NewClientWithOptions(
+		client.WithAccountIDParam("account_id"),
+		client.WithUserAgent("terraform-provider-zesty/1.2.3"),
+		client.WithRateLimit(5),
+		client.WithReadOnly(true),
+		client.WithExtraHeaders(headers),
+		client.WithLogLevel(hclog.Debug),
+		client.WithStrictDecoding(true),
+		client.WithRetryBudget(10),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "account_id", c.AccountIDParam)
+	assert.Equal(t, "terraform-provider-zesty/1.2.3", c.UserAgent)
+	assert.Equal(t, rate.Limit(5), c.Limiter.Limit())
+	assert.True(t, c.ReadOnly)
+	assert.Equal(t, headers, c.ExtraHeaders)
+	assert.Equal(t, hclog.Debug, c.LogLevel)
+	assert.True(t, c.StrictDecoding)
+	if assert.NotNil(t, c.RetryBudget) {
+		assert.Equal(t, int64(10), c.RetryBudget.Remaining())
+	}
+
+	// The client keeps its own copy of the headers.
+	headers["X-Tenant-ID"] = "tenant-2"
+	assert.Equal(t, "tenant-1", c.ExtraHeaders["X-Tenant-ID"])
+}
+
+func TestNewClientWithOptions_HTTPClient(t *testing.T) {
+	transport := &http.Transport{}
+	httpClient := &http.Client{Timeout: time.Minute, Transport: transport}
+
+	t.Run("keeps the client's timeout", func(t *testing.T) {
+		c, err := client.NewClientWithOptions(client.WithHTTPClient(httpClient))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, c.HTTPClient.Timeout)
+		assert.Same(t, transport, c.HTTPClient.Transport)
+	})
+
+	// WithTimeout applies whatever the order of the options, without modifying the given client.
+	for name, opts := range map[string][]client.Option{
+		"timeout first": {client.WithTimeout(time.Second), client.WithHTTPClient(httpClient)},
+		"timeout last":  {client.WithHTTPClient(httpClient), client.WithTimeout(time.Second)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, err := client.NewClientWithOptions(opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, time.Second, c.HTTPClient.Timeout)
+			assert.Same(t, transport, c.HTTPClient.Transport)
+			assert.Equal(t, time.Minute, httpClient.Timeout)
+		})
+	}
+}
+
+func TestNewClientWithOptions_LastOptionWins(t *testing.T) {
+	c, err := client.NewClientWithOptions(
+		client.WithAuthHeader("first-token"),
+		client.WithValidateAttempts(2),
+		client.WithAuthHeader("second-token"),
+		client.WithValidateAttempts(4),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "second-token", c.Token)
+	assert.Equal(t, 4, c.ValidateAttempts)
+}
+
+func TestNewClientWithOptions_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		option        client.Option
+		expectedError string
+	}{
+		"host":              {option: client.WithHost("api.zesty.co"), expectedError: `invalid host URL "api.zesty.co"`},
+		"timeout":           {option: client.WithTimeout(0), expectedError: "timeout must be positive, got 0s"},
+		"validate attempts": {option: client.WithValidateAttempts(0), expectedError: "attempts must be at least 1, got 0"},
+		"http client":       {option: client.WithHTTPClient(nil), expectedError: "HTTP client must not be nil"},
+		"retry budget":      {option: client.WithRetryBudget(-1), expectedError: "retry budget must not be negative, got -1"},
+		"user agent":        {option: client.WithUserAgent(" "), expectedError: "user agent must not be empty"},
+		"rate limit":        {option: client.WithRateLimit(0), expectedError: "rate limit must be positive, got 0"},
+		"extra headers": {
+			option:        client.WithExtraHeaders(map[string]string{"X-Tenant-ID": "tenant-1", "x-api-key": "other-token"}),
+			expectedError: "header x-api-key is set by the client and cannot be overridden",
+		},
+		"retry attempts": {
+			option:        client.WithRetryConfig(client.RetryConfig{}),
+			expectedError: "max attempts must be at least 1, got 0",
+		},
+		"retry waits": {
+			option:        client.WithRetryConfig(client.RetryConfig{MaxAttempts: 3, WaitMin: time.Minute, WaitMax: time.Second}),
+			expectedError: "maximum retry wait 1s must not be less than the minimum retry wait 1m0s",
+		},
+		"retry status": {
+			option:        client.WithRetryConfig(client.RetryConfig{MaxAttempts: 3, RetryOnStatus: []int{503, 42}}),
+			expectedError: "retry status codes must be between 100 and 599, got 42",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := client.NewClientWithOptions(tt.option)
+			assert.ErrorContains(t, err, tt.expectedError)
+			assert.Nil(t, c)
+		})
+	}
+}
+
+func TestNewClientWithOptions_RetryConfig(t *testing.T) {
+	c, err := client.NewClientWithOptions(client.WithRetryConfig(client.RetryConfig{MaxAttempts: 3, RetryOnStatus: []int{409}}))
+	assert.NoError(t, err)
+	assert.Equal(t, client.RetryConfig{
+		MaxAttempts:   3,
+		WaitMin:       client.DefaultRetryWaitMin,
+		WaitMax:       client.DefaultRetryWaitMax,
+		RetryOnStatus: []int{409},
+	}, c.Retry)
+
+	c, err = client.NewClientWithOptions(client.WithRetryConfig(client.RetryConfig{MaxAttempts: 3, WaitMin: time.Minute}))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, c.Retry.WaitMax)
+}
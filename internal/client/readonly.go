@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+// logReadOnly records a mutating request skipped because the client is in ReadOnly mode.
+func logReadOnly(ctx context.Context, operation string, payload models.Payload) {
+	tflog.Info(ctx, "Read-only mode, not sending Zesty API request", map[string]any{
+		"operation":  operation,
+		"account_id": payload.AccountID,
+	})
+}
+
+// accountFromPayload returns the account the API is expected to respond with for payload.
+func accountFromPayload(payload models.Payload) *models.Account {
+	additionalData := map[string]any{}
+	for key, value := range payload.AdditionalData {
+		additionalData[key] = value
+	}
+	additionalData["roleARN"] = payload.RoleARN
+	additionalData["externalID"] = payload.ExternalID
+
+	return &models.Account{
+		OrganizationID:   payload.OrganizationID,
+		AccountID:        payload.AccountID,
+		StorageClassName: payload.StorageClassName,
+		Region:           payload.Region,
+		CloudProvider:    payload.CloudProvider,
+		Products:         payload.Products,
+		Cur:              payload.Cur,
+		Athena:           payload.Athena,
+		SubscriptionID:   payload.SubscriptionID,
+		ProjectID:        payload.ProjectID,
+		Tags:             payload.Tags,
+		AdditionalData:   additionalData,
+	}
+}
@@ -0,0 +1,93 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+func TestClient_ReadOnly(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	c.ReadOnly = true
+
+	region := "us-east-1"
+	payload := models.Payload{
+		AccountID:     "acc123",
+		CloudProvider: models.AWS,
+		Region:        &region,
+		RoleARN:       "arn:aws:iam::123456789012:role/ZestyIamRole",
+		ExternalID:    "external-id",
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {Active: true},
+		},
+		AdditionalData: map[string]any{"scanCoverage": 80.0},
+	}
+	expected := &models.Account{
+		AccountID:     "acc123",
+		CloudProvider: models.AWS,
+		Region:        &region,
+		Products:      payload.Products,
+		AdditionalData: map[string]any{
+			"roleARN":      "arn:aws:iam::123456789012:role/ZestyIamRole",
+			"externalID":   "external-id",
+			"scanCoverage": 80.0,
+		},
+	}
+
+	ctx := context.Background()
+	account, err := c.CreateAccount(ctx, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, account)
+
+	account, err = c.UpdateAccount(ctx, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, account)
+
+	account, err = c.UpdateAccountPartial(ctx, models.Payload{AccountID: "acc123"}, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, account)
+
+	assert.NoError(t, c.DeleteAccount(ctx, payload))
+	assert.NoError(t, c.WaitForAccountDeleted(ctx, payload.AccountID))
+	assert.Empty(t, requests)
+
+	_, err = c.GetAccount(ctx, "acc123")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{http.MethodGet}, requests)
+}
+
+func TestClient_ReadOnly_SetProductActive(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"accountID":"acc123","cloudProvider":"AWS","products":{"Kompass":{"active":true,"values":{"threshold":80}},"CM":{"active":false}}}`))
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "testtoken")
+	c.ReadOnly = true
+
+	account, err := c.SetProductActive(context.Background(), "acc123", models.CM, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{http.MethodGet}, requests)
+	assert.Equal(t, "acc123", account.AccountID)
+	assert.Equal(t, models.AWS, account.CloudProvider)
+	assert.Equal(t, map[models.Product]models.ProductDetails{
+		models.Kompass: {Active: true, Values: map[string]any{"threshold": float64(80)}},
+		models.CM:      {Active: true},
+	}, account.Products)
+}
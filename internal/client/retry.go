@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RetryBudget caps the total number of retries sent by a client, shared by all concurrent
+// requests. It keeps many resources failing at once from multiplying their retries against a
+// struggling API. Tokens are never refilled, as the provider process lives for a single run.
+type RetryBudget struct {
+	remaining atomic.Int64
+}
+
+// NewRetryBudget returns a budget allowing the given number of retries in total.
+func NewRetryBudget(retries int64) *RetryBudget {
+	b := &RetryBudget{}
+	b.remaining.Store(retries)
+	return b
+}
+
+// Take spends one retry from the budget, reporting false when the budget is exhausted. A nil
+// budget is unlimited.
+func (b *RetryBudget) Take() bool {
+	if b == nil {
+		return true
+	}
+	return b.remaining.Add(-1) >= 0
+}
+
+// Remaining returns the number of retries left in the budget.
+func (b *RetryBudget) Remaining() int64 {
+	return max(b.remaining.Load(), 0)
+}
+
+// DefaultRetryWaitMin and DefaultRetryWaitMax bound the waits between retries of DoRequest unless
+// configured otherwise.
+const (
+	DefaultRetryWaitMin = time.Second
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// RetryConfig configures how DoRequest retries failed requests. The zero value disables retries.
+type RetryConfig struct {
+	// MaxAttempts is the number of times a request is sent before giving up. Zero or one disables
+	// retries.
+	MaxAttempts int
+	// WaitMin is the wait before the first retry, doubled after each retry up to WaitMax. Waits are
+	// jittered down to half their length.
+	WaitMin time.Duration
+	WaitMax time.Duration
+	// RetryOnStatus lists the status codes of the responses that are retried. Empty means 429 and
+	// any 5xx. Network errors reported by IsTemporary are retried either way.
+	RetryOnStatus []int
+}
+
+// shouldRetry reports whether a request that failed with err is worth retrying.
+func (r RetryConfig) shouldRetry(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && len(r.RetryOnStatus) > 0 {
+		return slices.Contains(r.RetryOnStatus, apiErr.StatusCode)
+	}
+	return IsTemporary(err)
+}
+
+// backoff returns the jittered wait before the given retry, starting at 1.
+func (r RetryConfig) backoff(retry int) time.Duration {
+	wait := r.WaitMin
+	for i := 1; i < retry && wait < r.WaitMax; i++ {
+		wait *= 2
+	}
+	return jitter(min(wait, r.WaitMax))
+}
+
+// doRequestWithRetries sends req with doRequest, retrying failures as configured by c.Retry while
+// the retry budget allows. Requests with a body are only retried when it can be replayed with
+// GetBody.
+func (c *Client) doRequestWithRetries(req *http.Request) ([]byte, int, error) {
+	ctx := req.Context()
+	body, statusCode, err := c.doRequest(req)
+	for attempt := 1; err != nil && attempt < c.Retry.MaxAttempts && c.Retry.shouldRetry(err); attempt++ {
+		if ctx.Err() != nil || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			break
+		}
+		if !c.RetryBudget.Take() {
+			tflog.Warn(ctx, "Not retrying Zesty API request, the retry budget is exhausted", map[string]any{
+				"attempt": attempt,
+				"error":   err.Error(),
+			})
+			break
+		}
+
+		wait := c.Retry.backoff(attempt)
+		logRetry(ctx, "Retrying Zesty API request", attempt, err, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return body, statusCode, err
+		case <-timer.C:
+		}
+
+		retry := req.Clone(ctx)
+		if req.GetBody != nil {
+			retry.Body, err = req.GetBody()
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		body, statusCode, err = c.doRequest(retry)
+	}
+	return body, statusCode, err
+}
+
+// jitter returns a random duration between half of backoff and backoff, so that clients retrying
+// at the same time spread their retries out instead of hitting the API together again.
+func jitter(backoff time.Duration) time.Duration {
+	if backoff <= 1 {
+		return backoff
+	}
+	half := backoff / 2
+	return half + rand.N(backoff-half+1)
+}
+
+// ErrPollTimeout is wrapped by the error PollUntil returns when maxWait elapses before fn is done.
+var ErrPollTimeout = errors.New("poll timed out")
+
+// PollUntil calls fn until it reports done, returns an error or maxWait elapses. It waits about
+// interval before the second call and doubles the wait after each call, jittering waits down to half
+// their length. Errors returned by fn are returned as is. When maxWait elapses, the error wraps
+// ErrPollTimeout, and when ctx is done first, it wraps ctx.Err(). A maxWait of zero or less polls
+// until ctx is done.
+func PollUntil(ctx context.Context, interval, maxWait time.Duration, fn func() (done bool, err error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("poll interval must be positive, got %s", interval)
+	}
+
+	pollCtx := ctx
+	if maxWait > 0 {
+		var cancel context.CancelFunc
+		pollCtx, cancel = context.WithTimeout(ctx, maxWait)
+		defer cancel()
+	}
+
+	wait := interval
+	for polls := 1; ; polls++ {
+		done, err := fn()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		timer := time.NewTimer(jitter(wait))
+		select {
+		case <-pollCtx.Done():
+			timer.Stop()
+			if ctx.Err() != nil {
+				return fmt.Errorf("not done after %d polls: %w", polls, ctx.Err())
+			}
+			return fmt.Errorf("not done after %d polls in %s: %w", polls, maxWait, ErrPollTimeout)
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
@@ -0,0 +1,316 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+)
+
+func TestRetryBudget(t *testing.T) {
+	budget := client.NewRetryBudget(2)
+
+	assert.True(t, budget.Take())
+	assert.Equal(t, int64(1), budget.Remaining())
+	assert.True(t, budget.Take())
+	assert.False(t, budget.Take())
+	assert.False(t, budget.Take())
+	assert.Equal(t, int64(0), budget.Remaining())
+}
+
+func TestRetryBudget_Nil(t *testing.T) {
+	var budget *client.RetryBudget
+	for i := 0; i < 100; i++ {
+		assert.True(t, budget.Take())
+	}
+}
+
+func TestRetryBudget_Concurrent(t *testing.T) {
+	budget := client.NewRetryBudget(25)
+
+	var taken atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if budget.Take() {
+					taken.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(25), taken.Load())
+	assert.Equal(t, int64(0), budget.Remaining())
+}
+
+func TestClient_Validate_RetryBudget(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "token")
+	c.ValidateAttempts = 5
+	c.ValidateBackoff = time.Millisecond
+	c.RetryBudget = client.NewRetryBudget(3)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := c.Validate(context.Background())
+			assert.True(t, client.IsTemporary(err))
+		}()
+	}
+	wg.Wait()
+
+	// Each caller sends its first request, and only 3 retries are shared among them, instead of
+	// the 4 retries each caller would send without a budget.
+	assert.Equal(t, int64(callers+3), requests.Load())
+	assert.Equal(t, int64(0), c.RetryBudget.Remaining())
+}
+
+func TestClient_Validate_NoRetryBudget(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "token")
+	c.ValidateBackoff = time.Millisecond
+	c.RetryBudget = client.NewRetryBudget(0)
+
+	err := c.Validate(context.Background())
+
+	var validationErr *client.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, 1, validationErr.Attempts)
+	assert.Equal(t, int64(1), requests.Load())
+}
+
+func TestClient_Validate_JitteredBackoff(t *testing.T) {
+	const backoff = 40 * time.Millisecond
+	for i := 0; i < 5; i++ {
+		var times []time.Time
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			times = append(times, time.Now())
+			if len(times) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		c, _ := client.NewClient(&server.URL, "testtoken")
+		c.ValidateBackoff = backoff
+
+		assert.NoError(t, c.Validate(context.Background()))
+		server.Close()
+
+		if assert.Len(t, times, 2) {
+			wait := times[1].Sub(times[0])
+			assert.GreaterOrEqual(t, wait, backoff/2)
+			assert.Less(t, wait, time.Second)
+		}
+	}
+}
+
+func TestClient_DoRequest_Retry(t *testing.T) {
+	tests := []struct {
+		name             string
+		retry            client.RetryConfig
+		status           int
+		failures         int
+		expectedRequests int
+		expectedError    bool
+	}{
+		{name: "disabled by default", status: http.StatusServiceUnavailable, failures: 1, expectedRequests: 1, expectedError: true},
+		{
+			name:             "retries temporary failures",
+			retry:            client.RetryConfig{MaxAttempts: 3},
+			status:           http.StatusServiceUnavailable,
+			failures:         2,
+			expectedRequests: 3,
+		},
+		{
+			name:             "gives up after max attempts",
+			retry:            client.RetryConfig{MaxAttempts: 3},
+			status:           http.StatusServiceUnavailable,
+			failures:         5,
+			expectedRequests: 3,
+			expectedError:    true,
+		},
+		{
+			name:             "does not retry other failures",
+			retry:            client.RetryConfig{MaxAttempts: 3},
+			status:           http.StatusConflict,
+			failures:         1,
+			expectedRequests: 1,
+			expectedError:    true,
+		},
+		{
+			name:             "retries configured status codes",
+			retry:            client.RetryConfig{MaxAttempts: 3, RetryOnStatus: []int{http.StatusConflict}},
+			status:           http.StatusConflict,
+			failures:         2,
+			expectedRequests: 3,
+		},
+		{
+			name:             "only retries configured status codes",
+			retry:            client.RetryConfig{MaxAttempts: 3, RetryOnStatus: []int{http.StatusConflict}},
+			status:           http.StatusServiceUnavailable,
+			failures:         1,
+			expectedRequests: 1,
+			expectedError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				if requests <= tt.failures {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			c, _ := client.NewClient(&server.URL, "token")
+			c.Retry = tt.retry
+			c.Retry.WaitMin = time.Millisecond
+			c.Retry.WaitMax = 2 * time.Millisecond
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/account", strings.NewReader(`{"accountID":"acc123"}`))
+			assert.NoError(t, err)
+			_, statusCode, err := c.DoRequest(req)
+
+			assert.Equal(t, tt.expectedRequests, requests)
+			if tt.expectedError {
+				var apiErr *client.APIError
+				assert.ErrorAs(t, err, &apiErr)
+				assert.Equal(t, tt.status, statusCode)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, statusCode)
+			}
+			// Every attempt sends the whole body again.
+			for _, body := range bodies {
+				assert.Equal(t, `{"accountID":"acc123"}`, body)
+			}
+		})
+	}
+}
+
+func TestClient_DoRequest_RetryBudget(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := client.NewClient(&server.URL, "token")
+	c.Retry = client.RetryConfig{MaxAttempts: 5, WaitMin: time.Millisecond, WaitMax: time.Millisecond}
+	c.RetryBudget = client.NewRetryBudget(2)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/accounts", nil)
+	assert.NoError(t, err)
+	_, _, err = c.DoRequest(req)
+	assert.True(t, client.IsTemporary(err))
+	assert.Equal(t, 3, requests)
+}
+
+func TestPollUntil(t *testing.T) {
+	t.Run("done", func(t *testing.T) {
+		var calls int
+		err := client.PollUntil(context.Background(), time.Millisecond, time.Second, func() (bool, error) {
+			calls++
+			return calls == 3, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("done on the first call", func(t *testing.T) {
+		var calls int
+		err := client.PollUntil(context.Background(), time.Hour, time.Second, func() (bool, error) {
+			calls++
+			return true, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		var calls int
+		start := time.Now()
+		err := client.PollUntil(context.Background(), time.Millisecond, 50*time.Millisecond, func() (bool, error) {
+			calls++
+			return false, nil
+		})
+		assert.ErrorIs(t, err, client.ErrPollTimeout)
+		assert.ErrorContains(t, err, "in 50ms")
+		assert.Less(t, time.Since(start), time.Second)
+		// The wait doubles after each call, so the calls are far fewer than 50.
+		assert.Greater(t, calls, 1)
+		assert.Less(t, calls, 10)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		failure := errors.New("boom")
+		var calls int
+		err := client.PollUntil(context.Background(), time.Millisecond, time.Second, func() (bool, error) {
+			calls++
+			if calls == 2 {
+				return false, failure
+			}
+			return false, nil
+		})
+		assert.Same(t, failure, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := client.PollUntil(ctx, time.Hour, 0, func() (bool, error) {
+			cancel()
+			return false, nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, client.ErrPollTimeout)
+		assert.EqualError(t, err, "not done after 1 polls: context canceled")
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		err := client.PollUntil(context.Background(), 0, time.Second, func() (bool, error) {
+			t.Fatal("fn must not be called")
+			return false, nil
+		})
+		assert.EqualError(t, err, "poll interval must be positive, got 0s")
+	})
+}
@@ -0,0 +1,168 @@
+package client_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+)
+
+func TestNewTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(caCertFile, caCert, 0o600))
+
+	invalidCertFile := filepath.Join(t.TempDir(), "invalid.pem")
+	assert.NoError(t, os.WriteFile(invalidCertFile, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name            string
+		config          client.TransportConfig
+		expectError     bool
+		expectRequestOK bool
+	}{
+		{
+			name:            "default transport rejects private CA",
+			config:          client.TransportConfig{},
+			expectRequestOK: false,
+		},
+		{
+			name:            "custom CA is trusted",
+			config:          client.TransportConfig{CACertFile: caCertFile},
+			expectRequestOK: true,
+		},
+		{
+			name:            "insecure skip verify",
+			config:          client.TransportConfig{InsecureSkipVerify: true},
+			expectRequestOK: true,
+		},
+		{
+			name:        "missing CA file",
+			config:      client.TransportConfig{CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+			expectError: true,
+		},
+		{
+			name:        "file without certificates",
+			config:      client.TransportConfig{CACertFile: invalidCertFile},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := client.NewTransport(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			c, err := client.NewClient(&server.URL, "testtoken")
+			assert.NoError(t, err)
+			c.HTTPClient.Transport = transport
+
+			err = c.Validate(context.Background())
+			if tt.expectRequestOK {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewTransport_ProxyURL(t *testing.T) {
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	transport, err := client.NewTransport(client.TransportConfig{ProxyURL: proxy.URL})
+	assert.NoError(t, err)
+
+	host := "http://zesty.invalid"
+	c, err := client.NewClient(&host, "testtoken")
+	assert.NoError(t, err)
+	c.HTTPClient.Transport = transport
+
+	err = c.Validate(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "http://zesty.invalid/validate", proxiedURL)
+}
+
+func TestNewTransport_InvalidProxyURL(t *testing.T) {
+	for _, proxyURL := range []string{"ftp://proxy:21", "://missing-scheme"} {
+		t.Run(proxyURL, func(t *testing.T) {
+			_, err := client.NewTransport(client.TransportConfig{ProxyURL: proxyURL})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewTransport_ConnectionPool(t *testing.T) {
+	transport, err := client.NewTransport(client.TransportConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, client.DefaultMaxIdleConns, transport.MaxIdleConns)
+	assert.Equal(t, client.DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, client.DefaultIdleConnTimeout, transport.IdleConnTimeout)
+
+	transport, err = client.NewTransport(client.TransportConfig{
+		MaxIdleConns:        20,
+		MaxIdleConnsPerHost: 5,
+		IdleConnTimeout:     30 * time.Second,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 20, transport.MaxIdleConns)
+	assert.Equal(t, 5, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout)
+}
+
+// countingListener counts the connections accepted by a test server.
+type countingListener struct {
+	net.Listener
+	accepted atomic.Int32
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.accepted.Add(1)
+	}
+	return conn, err
+}
+
+func TestClient_ReusesConnections(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"accountID":"acc123"}`))
+	}))
+	listener := &countingListener{Listener: server.Listener}
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "testtoken")
+	assert.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		_, err := c.GetAccount(context.Background(), "acc123")
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), listener.accepted.Load())
+}
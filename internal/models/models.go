@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type (
 	OnboardingStatus string
@@ -12,16 +18,206 @@ const (
 	AWS   CloudProvider = "AWS"
 	Azure CloudProvider = "Azure"
 	GCP   CloudProvider = "GCP"
+	OCI   CloudProvider = "OCI"
 
 	Kompass   Product = "Kompass"
 	CM        Product = "CM"
 	ZestyDisk Product = "ZestyDisk"
 
+	OnboardingCompleted OnboardingStatus = "Completed"
+
+	// DefaultHostURL is the production Zesty API, used by the client and the provider when no
+	// host is configured.
 	DefaultHostURL string = "https://api.zesty.co/kompass-platform"
+
+	// APIVersion is the version of the Zesty API the payloads in this package are shaped for.
+	APIVersion string = "v2"
 )
 
+// CompatibleAPIVersion reports whether an API reporting version accepts the payloads of APIVersion.
+// Versions are compatible when their major versions match, so v2 and v2.1 are compatible but v1 is
+// not.
+func CompatibleAPIVersion(version string) bool {
+	return majorAPIVersion(version) == majorAPIVersion(APIVersion)
+}
+
+// majorAPIVersion returns the major part of version, such as 2 for v2.1.
+func majorAPIVersion(version string) string {
+	version = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
+
+// ReadyOnboardingStatuses lists the terminal onboarding statuses of an account that is fully onboarded,
+// unless the provider's ready_onboarding_statuses attribute lists others. The API does not document
+// its statuses, so they can be adjusted without a provider release.
+var ReadyOnboardingStatuses = []OnboardingStatus{OnboardingCompleted}
+
+// Ready reports whether s is one of the ReadyOnboardingStatuses.
+func (s OnboardingStatus) Ready() bool {
+	return s.ReadyIn(ReadyOnboardingStatuses)
+}
+
+// ReadyIn reports whether s is one of statuses.
+func (s OnboardingStatus) ReadyIn(statuses []OnboardingStatus) bool {
+	for _, status := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CloudProviders lists every cloud provider accounts can be onboarded from.
+var CloudProviders = []CloudProvider{AWS, Azure, GCP, OCI}
+
+// Valid reports whether c is one of the known CloudProviders.
+func (c CloudProvider) Valid() bool {
+	_, err := ParseCloudProvider(string(c))
+	return err == nil
+}
+
+// ParseCloudProvider returns the one of the CloudProviders named s. Names are case-sensitive, so
+// that configuration matches what the API returns; a name differing only in case is rejected with
+// the correct spelling suggested.
+func ParseCloudProvider(s string) (CloudProvider, error) {
+	for _, cloudProvider := range CloudProviders {
+		if s == string(cloudProvider) {
+			return cloudProvider, nil
+		}
+	}
+
+	names := make([]string, len(CloudProviders))
+	for i, cloudProvider := range CloudProviders {
+		if strings.EqualFold(s, string(cloudProvider)) {
+			return "", fmt.Errorf("cloud provider %q is not supported, did you mean %q?", s, cloudProvider)
+		}
+		names[i] = string(cloudProvider)
+	}
+	return "", fmt.Errorf("cloud provider %q is not supported, must be one of: %s", s, strings.Join(names, ", "))
+}
+
+// AWSRegions lists the identifiers of the AWS regions accounts can be onboarded in, including the
+// GovCloud and China regions. It must be extended as AWS opens new regions.
+var AWSRegions = []string{
+	"af-south-1",
+	"ap-east-1",
+	"ap-east-2",
+	"ap-northeast-1",
+	"ap-northeast-2",
+	"ap-northeast-3",
+	"ap-south-1",
+	"ap-south-2",
+	"ap-southeast-1",
+	"ap-southeast-2",
+	"ap-southeast-3",
+	"ap-southeast-4",
+	"ap-southeast-5",
+	"ap-southeast-6",
+	"ap-southeast-7",
+	"ca-central-1",
+	"ca-west-1",
+	"cn-north-1",
+	"cn-northwest-1",
+	"eu-central-1",
+	"eu-central-2",
+	"eu-north-1",
+	"eu-south-1",
+	"eu-south-2",
+	"eu-west-1",
+	"eu-west-2",
+	"eu-west-3",
+	"il-central-1",
+	"me-central-1",
+	"me-south-1",
+	"mx-central-1",
+	"sa-east-1",
+	"us-east-1",
+	"us-east-2",
+	"us-gov-east-1",
+	"us-gov-west-1",
+	"us-west-1",
+	"us-west-2",
+}
+
+// Products lists every product known to the provider.
+var Products = []Product{Kompass, CM, ZestyDisk}
+
+// ProductDescriptions holds a short human-readable description of each of the Products.
+var ProductDescriptions = map[Product]string{
+	Kompass:   "Kubernetes cost optimization platform",
+	CM:        "Commitment Manager for reserved instances and savings plans",
+	ZestyDisk: "Automatically scaling block storage",
+}
+
+// Valid reports whether p is one of the known Products.
+func (p Product) Valid() bool {
+	for _, product := range Products {
+		if p == product {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductAliases maps the deprecated names of renamed products to their current names, so
+// configurations written before a rename keep working. Payloads are sent with the current name.
+var ProductAliases = map[Product]Product{}
+
+// Canonical returns the current name of p, and whether p is a deprecated name in ProductAliases.
+func (p Product) Canonical() (Product, bool) {
+	if current, ok := ProductAliases[p]; ok {
+		return current, true
+	}
+	return p, false
+}
+
+// ProductCloudProviders maps a product to the cloud providers it is available on. Products missing
+// from the map are available on every cloud provider.
+var ProductCloudProviders = map[Product][]CloudProvider{
+	ZestyDisk: {AWS},
+}
+
+// SupportedOn reports whether p is available on cloudProvider according to ProductCloudProviders.
+func (p Product) SupportedOn(cloudProvider CloudProvider) bool {
+	supported, ok := ProductCloudProviders[p]
+	if !ok {
+		return true
+	}
+	for _, c := range supported {
+		if c == cloudProvider {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductDependencies maps a product to the products that must also be active for it to be activated.
+// None of the current products depends on another, so it is empty until one does.
+var ProductDependencies = map[Product][]Product{}
+
+// MissingDependencies returns the prerequisites of p that are not active in products.
+func (p Product) MissingDependencies(products map[Product]ProductDetails) []Product {
+	var missing []Product
+	for _, dependency := range ProductDependencies[p] {
+		if !products[dependency].Active {
+			missing = append(missing, dependency)
+		}
+	}
+	return missing
+}
+
 type ProductDetails struct {
-	Active bool `json:"active" dynamodbav:"active"`
+	Active bool           `json:"active" dynamodbav:"active"`
+	Values map[string]any `json:"values,omitempty" dynamodbav:"values,omitempty"`
+	// Region overrides the account's region for this product. Nil means the account region applies.
+	Region *string `json:"region,omitempty" dynamodbav:"region,omitempty"`
+}
+
+// AccountSnapshot is the state of an account's products at a point in its history.
+type AccountSnapshot struct {
+	Timestamp time.Time                  `json:"timestamp"`
+	Products  map[Product]ProductDetails `json:"products"`
 }
 
 type CurDetails struct {
@@ -50,10 +246,25 @@ type Payload struct {
 	Products         map[Product]ProductDetails `json:"products"`
 	Cur              *CurDetails                `json:"cur,omitempty"`
 	Athena           *AthenaDetails             `json:"athena,omitempty"`
+	AdditionalData   map[string]any             `json:"additionalData,omitempty"`
+	OrganizationID   int64                      `json:"organizationID,omitempty"`
+	SubscriptionID   string                     `json:"subscriptionID,omitempty"`
+	ProjectID        string                     `json:"projectID,omitempty"`
+	Tags             map[string]string          `json:"tags,omitempty"`
+}
+
+// MarshalJSON encodes the payload with a nil Products map as an empty products object rather than
+// null, as the API rejects payloads without products.
+func (p Payload) MarshalJSON() ([]byte, error) {
+	type payload Payload
+	if p.Products == nil {
+		p.Products = map[Product]ProductDetails{}
+	}
+	return json.Marshal(payload(p))
 }
 
 type Account struct {
-	OrganizationID   int64
+	OrganizationID   int64 `json:"organizationID"`
 	OnboardingStatus OnboardingStatus
 	AccountID        string
 	StorageClassName string
@@ -62,8 +273,45 @@ type Account struct {
 	Products         map[Product]ProductDetails
 	Cur              *CurDetails
 	Athena           *AthenaDetails
+	SubscriptionID   string
+	ProjectID        string
+	Tags             map[string]string
 
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
 	AdditionalData map[string]any
 }
+
+// UnmarshalJSON decodes an account, accepting its accountID as a JSON number as well as a string,
+// as the API returns the IDs of some cloud providers as numbers. Numbers are kept as written, so
+// large IDs do not lose precision.
+func (a *Account) UnmarshalJSON(data []byte) error {
+	type account Account
+	decoded := struct {
+		*account
+		AccountID json.RawMessage
+	}{account: (*account)(a)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.AccountID == nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(decoded.AccountID))
+	decoder.UseNumber()
+	var id any
+	if err := decoder.Decode(&id); err != nil {
+		return err
+	}
+	switch id := id.(type) {
+	case nil:
+	case string:
+		a.AccountID = id
+	case json.Number:
+		a.AccountID = id.String()
+	default:
+		return fmt.Errorf("account ID must be a string or a number, got %s", decoded.AccountID)
+	}
+	return nil
+}
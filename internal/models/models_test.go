@@ -0,0 +1,298 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+func TestProduct_MissingDependencies(t *testing.T) {
+	original := models.ProductDependencies
+	models.ProductDependencies = map[models.Product][]models.Product{
+		models.ZestyDisk: {models.Kompass},
+	}
+	t.Cleanup(func() { models.ProductDependencies = original })
+
+	tests := []struct {
+		name     string
+		product  models.Product
+		products map[models.Product]models.ProductDetails
+		expected []models.Product
+	}{
+		{
+			name:    "dependency is missing",
+			product: models.ZestyDisk,
+			products: map[models.Product]models.ProductDetails{
+				models.ZestyDisk: {Active: true},
+			},
+			expected: []models.Product{models.Kompass},
+		},
+		{
+			name:    "dependency is inactive",
+			product: models.ZestyDisk,
+			products: map[models.Product]models.ProductDetails{
+				models.ZestyDisk: {Active: true},
+				models.Kompass:   {Active: false},
+			},
+			expected: []models.Product{models.Kompass},
+		},
+		{
+			name:    "dependency is satisfied",
+			product: models.ZestyDisk,
+			products: map[models.Product]models.ProductDetails{
+				models.ZestyDisk: {Active: true},
+				models.Kompass:   {Active: true},
+			},
+			expected: nil,
+		},
+		{
+			name:    "product without dependencies",
+			product: models.CM,
+			products: map[models.Product]models.ProductDetails{
+				models.CM: {Active: true},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.product.MissingDependencies(tt.products))
+		})
+	}
+}
+
+func TestProduct_Valid(t *testing.T) {
+	for _, product := range models.Products {
+		assert.True(t, product.Valid(), product)
+	}
+	assert.False(t, models.Product("Komppass").Valid())
+	assert.False(t, models.Product("").Valid())
+}
+
+func TestProduct_Canonical(t *testing.T) {
+	original := models.ProductAliases
+	models.ProductAliases = map[models.Product]models.Product{
+		"CostManagement": models.CM,
+	}
+	t.Cleanup(func() { models.ProductAliases = original })
+
+	name, deprecated := models.Product("CostManagement").Canonical()
+	assert.Equal(t, models.CM, name)
+	assert.True(t, deprecated)
+
+	name, deprecated = models.Kompass.Canonical()
+	assert.Equal(t, models.Kompass, name)
+	assert.False(t, deprecated)
+}
+
+func TestProductAliases(t *testing.T) {
+	for alias, current := range models.ProductAliases {
+		assert.False(t, alias.Valid(), "alias %s must not be a current product", alias)
+		assert.True(t, current.Valid(), "alias %s must map to a known product", alias)
+	}
+}
+
+func TestProduct_SupportedOn(t *testing.T) {
+	original := models.ProductCloudProviders
+	models.ProductCloudProviders = map[models.Product][]models.CloudProvider{
+		models.ZestyDisk: {models.AWS},
+		models.CM:        {models.AWS, models.Azure},
+	}
+	t.Cleanup(func() { models.ProductCloudProviders = original })
+
+	tests := []struct {
+		product       models.Product
+		cloudProvider models.CloudProvider
+		expected      bool
+	}{
+		{product: models.ZestyDisk, cloudProvider: models.AWS, expected: true},
+		{product: models.ZestyDisk, cloudProvider: models.Azure, expected: false},
+		{product: models.CM, cloudProvider: models.Azure, expected: true},
+		{product: models.CM, cloudProvider: models.OCI, expected: false},
+		{product: models.Kompass, cloudProvider: models.GCP, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.product)+"/"+string(tt.cloudProvider), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.product.SupportedOn(tt.cloudProvider))
+		})
+	}
+}
+
+func TestProductCloudProviders(t *testing.T) {
+	assert.True(t, models.ZestyDisk.SupportedOn(models.AWS))
+	assert.False(t, models.ZestyDisk.SupportedOn(models.Azure))
+	for product, cloudProviders := range models.ProductCloudProviders {
+		assert.True(t, product.Valid(), product)
+		for _, cloudProvider := range cloudProviders {
+			assert.True(t, cloudProvider.Valid(), cloudProvider)
+		}
+	}
+}
+
+func TestProductDescriptions(t *testing.T) {
+	for _, product := range models.Products {
+		assert.NotEmpty(t, models.ProductDescriptions[product], product)
+	}
+}
+
+func TestCloudProvider_Valid(t *testing.T) {
+	for _, cloudProvider := range models.CloudProviders {
+		assert.True(t, cloudProvider.Valid(), cloudProvider)
+	}
+	assert.True(t, models.OCI.Valid())
+	assert.False(t, models.CloudProvider("aws").Valid())
+	assert.False(t, models.CloudProvider("Oracle").Valid())
+}
+
+func TestParseCloudProvider(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      models.CloudProvider
+		expectedError string
+	}{
+		{input: "AWS", expected: models.AWS},
+		{input: "Azure", expected: models.Azure},
+		{input: "GCP", expected: models.GCP},
+		{input: "OCI", expected: models.OCI},
+		{input: "aws", expectedError: `cloud provider "aws" is not supported, did you mean "AWS"?`},
+		{input: "AZURE", expectedError: `cloud provider "AZURE" is not supported, did you mean "Azure"?`},
+		{input: "Oracle", expectedError: `cloud provider "Oracle" is not supported, must be one of: AWS, Azure, GCP, OCI`},
+		{input: "", expectedError: `cloud provider "" is not supported, must be one of: AWS, Azure, GCP, OCI`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			cloudProvider, err := models.ParseCloudProvider(tt.input)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.Empty(t, cloudProvider)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cloudProvider)
+		})
+	}
+}
+
+func TestCompatibleAPIVersion(t *testing.T) {
+	for _, version := range []string{models.APIVersion, "v2", "V2", "2", "v2.1", " v2 "} {
+		assert.True(t, models.CompatibleAPIVersion(version), version)
+	}
+	for _, version := range []string{"v1", "1.9", "v3", "v20", ""} {
+		assert.False(t, models.CompatibleAPIVersion(version), version)
+	}
+}
+
+func TestOnboardingStatus_Ready(t *testing.T) {
+	for _, status := range models.ReadyOnboardingStatuses {
+		assert.True(t, status.Ready(), status)
+	}
+	assert.False(t, models.OnboardingStatus("Pending").Ready())
+	assert.False(t, models.OnboardingStatus("").Ready())
+}
+
+func TestOnboardingStatus_ReadyIn(t *testing.T) {
+	statuses := []models.OnboardingStatus{"Active", "Onboarded"}
+	assert.True(t, models.OnboardingStatus("Active").ReadyIn(statuses))
+	assert.True(t, models.OnboardingStatus("Onboarded").ReadyIn(statuses))
+	assert.False(t, models.OnboardingCompleted.ReadyIn(statuses))
+	assert.False(t, models.OnboardingCompleted.ReadyIn(nil))
+}
+
+func TestPayload_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		products map[models.Product]models.ProductDetails
+		expected string
+	}{
+		{
+			name:     "nil products",
+			products: nil,
+			expected: `{}`,
+		},
+		{
+			name:     "empty products",
+			products: map[models.Product]models.ProductDetails{},
+			expected: `{}`,
+		},
+		{
+			name:     "products",
+			products: map[models.Product]models.ProductDetails{models.Kompass: {Active: true}},
+			expected: `{"Kompass": {"active": true}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, payload := range []any{
+				models.Payload{AccountID: "acc", Products: tt.products},
+				&models.Payload{AccountID: "acc", Products: tt.products},
+			} {
+				encoded, err := json.Marshal(payload)
+				assert.NoError(t, err)
+
+				var fields map[string]json.RawMessage
+				assert.NoError(t, json.Unmarshal(encoded, &fields))
+				assert.Equal(t, "\"acc\"", string(fields["accountID"]))
+				assert.JSONEq(t, tt.expected, string(fields["products"]))
+			}
+		})
+	}
+}
+
+func TestAccount_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		expectedID    string
+		expectedError string
+	}{
+		{name: "string", body: `{"accountID": "123456789012"}`, expectedID: "123456789012"},
+		{name: "number", body: `{"accountID": 123456789012}`, expectedID: "123456789012"},
+		{name: "large number", body: `{"accountID": 98765432109876543210}`, expectedID: "98765432109876543210"},
+		{name: "null", body: `{"accountID": null}`, expectedID: ""},
+		{name: "missing", body: `{}`, expectedID: ""},
+		{name: "bool", body: `{"accountID": true}`, expectedError: "account ID must be a string or a number, got true"},
+		{name: "object", body: `{"accountID": {"id": 1}}`, expectedError: `account ID must be a string or a number, got {"id": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var account models.Account
+			err := json.Unmarshal([]byte(tt.body), &account)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedID, account.AccountID)
+		})
+	}
+}
+
+func TestAccount_UnmarshalJSON_Fields(t *testing.T) {
+	for _, id := range []string{`"123456789012"`, `123456789012`} {
+		var account models.Account
+		err := json.Unmarshal([]byte(`{
+			"accountID": `+id+`,
+			"organizationID": 42,
+			"cloudProvider": "AWS",
+			"region": "eu-west-1",
+			"products": {"Kompass": {"active": true}}
+		}`), &account)
+		assert.NoError(t, err)
+		assert.Equal(t, "123456789012", account.AccountID)
+		assert.Equal(t, int64(42), account.OrganizationID)
+		assert.Equal(t, models.AWS, account.CloudProvider)
+		assert.Equal(t, "eu-west-1", *account.Region)
+		assert.True(t, account.Products[models.Kompass].Active)
+	}
+
+	var accounts []models.Account
+	assert.NoError(t, json.Unmarshal([]byte(`[{"accountID": "acc123"}, {"accountID": 123}]`), &accounts))
+	assert.Equal(t, []string{"acc123", "123"}, []string{accounts[0].AccountID, accounts[1].AccountID})
+}
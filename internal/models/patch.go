@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// patchIdentifiers are the payload fields identifying the account, sent with every patch even when
+// unchanged.
+var patchIdentifiers = []string{"accountID", "cloudProvider", "organizationID"}
+
+// PayloadPatch returns a JSON merge patch (RFC 7386) turning prior into planned: fields that differ
+// are set, nested objects such as products are patched recursively, and fields missing from planned
+// are set to null. The fields identifying the account are always included.
+func PayloadPatch(prior Payload, planned Payload) (map[string]any, error) {
+	priorFields, err := payloadFields(prior)
+	if err != nil {
+		return nil, err
+	}
+	plannedFields, err := payloadFields(planned)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := mergePatch(priorFields, plannedFields)
+	for _, key := range patchIdentifiers {
+		if value, ok := plannedFields[key]; ok {
+			patch[key] = value
+		}
+	}
+	return patch, nil
+}
+
+// payloadFields returns the JSON object payload is encoded as.
+func payloadFields(payload Payload) (map[string]any, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func mergePatch(prior map[string]any, planned map[string]any) map[string]any {
+	patch := map[string]any{}
+	for key, value := range planned {
+		priorValue, ok := prior[key]
+		if ok && reflect.DeepEqual(priorValue, value) {
+			continue
+		}
+		priorObject, priorIsObject := priorValue.(map[string]any)
+		object, isObject := value.(map[string]any)
+		if priorIsObject && isObject {
+			patch[key] = mergePatch(priorObject, object)
+			continue
+		}
+		patch[key] = value
+	}
+	for key := range prior {
+		if _, ok := planned[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	return patch
+}
@@ -0,0 +1,90 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+func TestPayloadPatch(t *testing.T) {
+	region := "us-east-1"
+	prior := models.Payload{
+		AccountID:        "acc",
+		CloudProvider:    models.AWS,
+		Region:           &region,
+		RoleARN:          "arn:aws:iam::123456789012:role/example",
+		ExternalID:       "external-id",
+		StorageClassName: "gp3",
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {Active: true, Values: map[string]any{"cluster": "prod", "mode": "lite"}},
+			models.CM:      {Active: true},
+		},
+		Tags: map[string]string{"team": "platform"},
+	}
+
+	tests := []struct {
+		name     string
+		planned  func(p models.Payload) models.Payload
+		expected map[string]any
+	}{
+		{
+			name:     "unchanged",
+			planned:  func(p models.Payload) models.Payload { return p },
+			expected: map[string]any{"accountID": "acc", "cloudProvider": "AWS"},
+		},
+		{
+			name: "changed field",
+			planned: func(p models.Payload) models.Payload {
+				p.StorageClassName = "gp2"
+				return p
+			},
+			expected: map[string]any{"accountID": "acc", "cloudProvider": "AWS", "storageClassName": "gp2"},
+		},
+		{
+			name: "removed field",
+			planned: func(p models.Payload) models.Payload {
+				p.Region = nil
+				p.Tags = nil
+				return p
+			},
+			expected: map[string]any{"accountID": "acc", "cloudProvider": "AWS", "region": nil, "tags": nil},
+		},
+		{
+			name: "nested changes",
+			planned: func(p models.Payload) models.Payload {
+				p.Products = map[models.Product]models.ProductDetails{
+					models.Kompass: {Active: true, Values: map[string]any{"cluster": "dev", "mode": "lite"}},
+				}
+				p.Tags = map[string]string{"team": "platform", "env": "prod"}
+				return p
+			},
+			expected: map[string]any{
+				"accountID":     "acc",
+				"cloudProvider": "AWS",
+				"products": map[string]any{
+					"Kompass": map[string]any{"values": map[string]any{"cluster": "dev"}},
+					"CM":      nil,
+				},
+				"tags": map[string]any{"env": "prod"},
+			},
+		},
+		{
+			name: "organization",
+			planned: func(p models.Payload) models.Payload {
+				p.OrganizationID = 42
+				return p
+			},
+			expected: map[string]any{"accountID": "acc", "cloudProvider": "AWS", "organizationID": float64(42)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := models.PayloadPatch(prior, tt.planned(prior))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, patch)
+		})
+	}
+}
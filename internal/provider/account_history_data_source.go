@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+// AccountHistoryDataSource lists how the products of an account, and their values, changed over
+// time.
+type AccountHistoryDataSource struct {
+	client            *client.Client
+	valuesFormat      string
+	omitProductValues bool
+}
+
+var (
+	_ datasource.DataSource              = &AccountHistoryDataSource{}
+	_ datasource.DataSourceWithConfigure = &AccountHistoryDataSource{}
+)
+
+func NewAccountHistoryDataSource() datasource.DataSource {
+	return &AccountHistoryDataSource{}
+}
+
+func (d *AccountHistoryDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_account_history"
+}
+
+type accountHistoryDataSourceModel struct {
+	AccountID types.String           `tfsdk:"account_id"`
+	Available types.Bool             `tfsdk:"available"`
+	Snapshots []accountSnapshotModel `tfsdk:"snapshots"`
+}
+
+type accountSnapshotModel struct {
+	Timestamp types.String   `tfsdk:"timestamp"`
+	Products  []productModel `tfsdk:"products"`
+}
+
+// Schema defines the schema for the data source.
+func (d *AccountHistoryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the recorded snapshots of an account's products, to audit how their values changed over time. History is an optional endpoint: when the API answers it with a 404, available is false and snapshots is empty.",
+		Attributes: map[string]schema.Attribute{
+			"account_id": schema.StringAttribute{
+				Description: "ID of the account",
+				Required:    true,
+			},
+			"available": availableAttribute(),
+			"snapshots": schema.ListNestedAttribute{
+				Description: "Snapshots of the account's products, oldest first",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"timestamp": schema.StringAttribute{
+							Description: "Time the snapshot was recorded (RFC3339)",
+							Computed:    true,
+						},
+						"products": schema.ListNestedAttribute{
+							Description: "Products of the account at the time of the snapshot",
+							Computed:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										Description: "Name of product (e.g. Kompass)",
+										Computed:    true,
+									},
+									"active": schema.BoolAttribute{
+										Description: "Status of product",
+										Computed:    true,
+									},
+									"values": schema.StringAttribute{
+										Description: "Key-value pairs of product-specific values, encoded in the provider's values_format",
+										Computed:    true,
+									},
+									"values_map": schema.MapAttribute{
+										Description: "Product-specific values by key. Strings are kept as they are, and other values, including nested objects and lists, are encoded as JSON",
+										ElementType: types.StringType,
+										Computed:    true,
+									},
+									"values_hash": schema.StringAttribute{
+										Description: "SHA-256 checksum of the values, which only changes with their content, not with their encoding. Kept when the provider sets omit_product_values",
+										Computed:    true,
+									},
+									"region": schema.StringAttribute{
+										Description: "Region the product ran in, when it differed from the account region",
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AccountHistoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state accountHistoryDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	accountID := state.AccountID.ValueString()
+	snapshots, err := d.client.GetAccountHistory(ctx, accountID)
+	state.Available = types.BoolValue(endpointAvailable(err, "Unable to Read Zesty Account History", &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Received account history", map[string]any{"id": accountID, "count": len(snapshots), "available": state.Available.ValueBool()})
+
+	state.Snapshots = []accountSnapshotModel{}
+	for _, snapshot := range snapshots {
+		products, diags := ProductsFromPayloadMap(&models.Account{AccountID: accountID, Products: snapshot.Products}, d.valuesFormat)
+		resp.Diagnostics.Append(withAccountLabel(diags, fmt.Sprintf("snapshot %s", snapshot.Timestamp.UTC().Format(time.RFC3339)))...)
+		if diags.HasError() {
+			return
+		}
+		if d.omitProductValues {
+			omitProductValues(products)
+		}
+
+		state.Snapshots = append(state.Snapshots, accountSnapshotModel{
+			Timestamp: types.StringValue(snapshot.Timestamp.UTC().Format(time.RFC3339)),
+			Products:  products,
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *AccountHistoryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected: *providerData, got: %T.\nPlease report this issue to Zesty Support.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.client
+	d.valuesFormat = data.valuesFormat
+	d.omitProductValues = data.omitProductValues
+}
@@ -0,0 +1,81 @@
+package provider_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+func TestAccAccountHistoryDataSource(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.history["123456789012"] = []models.AccountSnapshot{
+		{
+			Timestamp: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			Products: map[models.Product]models.ProductDetails{
+				models.Kompass: {Active: true, Values: map[string]any{"threshold": 90}},
+			},
+		},
+		{
+			Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			Products: map[models.Product]models.ProductDetails{
+				models.Kompass: {Active: true, Values: map[string]any{"threshold": 80}},
+			},
+		},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_account_history" "test" {
+  account_id = "123456789012"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_account_history.test", "available", "true"),
+					resource.TestCheckResourceAttr("data.zesty_account_history.test", "snapshots.#", "2"),
+					resource.TestCheckResourceAttr("data.zesty_account_history.test", "snapshots.0.timestamp", "2024-01-01T12:00:00Z"),
+					resource.TestCheckResourceAttr("data.zesty_account_history.test", "snapshots.0.products.0.name", "Kompass"),
+					resource.TestCheckResourceAttr("data.zesty_account_history.test", "snapshots.0.products.0.values_map.threshold", "80"),
+					resource.TestCheckResourceAttr("data.zesty_account_history.test", "snapshots.1.timestamp", "2024-03-01T12:00:00Z"),
+					resource.TestCheckResourceAttr("data.zesty_account_history.test", "snapshots.1.products.0.values_map.threshold", "90"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_account_history" "test" {
+  account_id = "000000000000"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_account_history.test", "available", "false"),
+					resource.TestCheckResourceAttr("data.zesty_account_history.test", "snapshots.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountHistoryDataSource_Error(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.historyStatus = http.StatusForbidden
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_account_history" "test" {
+  account_id = "123456789012"
+}
+`,
+				ExpectError: regexp.MustCompile(`Unable\s+to\s+Read\s+Zesty\s+Account\s+History`),
+			},
+		},
+	})
+}
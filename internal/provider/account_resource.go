@@ -3,14 +3,21 @@ package provider
 import (
 	"context"
 	"fmt"
-	"time"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/zesty-co/terraform-provider-zesty/internal/client"
@@ -18,13 +25,24 @@ import (
 )
 
 type AccountResource struct {
-	client *client.Client
+	client               *client.Client
+	valuesFormat         string
+	defaultCloudProvider models.CloudProvider
+	// allowEmptyProducts lets accounts be planned with an empty products list.
+	allowEmptyProducts bool
+	// readyStatuses are the onboarding statuses that set the ready attribute of accounts.
+	readyStatuses []models.OnboardingStatus
+	// tenants maps the names of the provider's tenants to their tokens.
+	tenants map[string]string
+	// tenantClient is built by clientFor for accounts of one of the tenants.
+	tenantClient *client.Client
 }
 
 var (
 	_ resource.Resource                = &AccountResource{}
 	_ resource.ResourceWithConfigure   = &AccountResource{}
 	_ resource.ResourceWithImportState = &AccountResource{}
+	_ resource.ResourceWithModifyPlan  = &AccountResource{}
 )
 
 func NewAccountResource() resource.Resource {
@@ -36,13 +54,18 @@ func (r *AccountResource) Metadata(_ context.Context, req resource.MetadataReque
 }
 
 type accountResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Account     accountModel `tfsdk:"account"`
-	LastUpdated types.String `tfsdk:"last_updated"`
+	ID            types.String   `tfsdk:"id"`
+	Account       accountModel   `tfsdk:"account"`
+	LastUpdated   types.String   `tfsdk:"last_updated"`
+	ForceDestroy  types.Bool     `tfsdk:"force_destroy"`
+	VerifyDestroy types.Bool     `tfsdk:"verify_destroy"`
+	DeletionMode  types.String   `tfsdk:"deletion_mode"`
+	Tenant        types.String   `tfsdk:"tenant"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 // Schema defines the schema for the resource.
-func (r *AccountResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *AccountResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages an account.",
 		Attributes: map[string]schema.Attribute{
@@ -54,47 +77,144 @@ func (r *AccountResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				},
 			},
 			"last_updated": schema.StringAttribute{
-				Description: "Timestamp of the last Terraform update of the account.",
+				Description: "Time the account was last updated by Terraform (RFC3339), as reported by the Zesty API.",
 				Computed:    true,
 			},
+			"force_destroy": schema.BoolAttribute{
+				Description: "Treat the account as deleted when the Zesty API reports it no longer exists on destroy, instead of failing. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"verify_destroy": schema.BoolAttribute{
+				Description: "On destroy, poll the Zesty API after the account is deleted until it reports the account no longer exists, or the delete timeout expires. Catches deletions that the API accepts but that later fail. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"deletion_mode": schema.StringAttribute{
+				Description: "How the account is deleted on destroy. One of soft, which deactivates the account but keeps its data, or hard, which also purges its data. When unset, the Zesty API decides.",
+				Optional:    true,
+				Validators: []validator.String{
+					oneOfValidator{values: []string{string(client.DeletionModeSoft), string(client.DeletionModeHard)}},
+				},
+			},
+			"tenant": schema.StringAttribute{
+				Description: "Name of one of the tenants of the provider whose token is used for this account instead of the provider's token, for managing accounts of several tenants in one configuration. Only the name is kept in state, never the token. Accounts of a tenant are imported with an ID prefixed with the name of the tenant and a colon, such as tenant_b:123456789012.",
+				Optional:    true,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 			"account": schema.SingleNestedAttribute{
 				Required: true,
 				Attributes: map[string]schema.Attribute{
 					"id": schema.StringAttribute{
 						Description: "Account ID",
 						Required:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"organization_id": schema.Int64Attribute{
+						Description: "ID of the Zesty organization the account belongs to. Only needed when account IDs are not unique across organizations, and otherwise read from the API",
+						Optional:    true,
+						Computed:    true,
+						PlanModifiers: []planmodifier.Int64{
+							int64planmodifier.UseStateForUnknown(),
+							int64planmodifier.RequiresReplace(),
+						},
+					},
+					"subscription_id": schema.StringAttribute{
+						Description: "Azure subscription ID (GUID) of the account. Only valid when cloud_provider is Azure",
+						Optional:    true,
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+						Validators: []validator.String{
+							patternValidator{pattern: guidPattern, description: "a GUID such as 00000000-0000-0000-0000-000000000000"},
+						},
+					},
+					"project_id": schema.StringAttribute{
+						Description: "GCP project ID of the account. Only valid when cloud_provider is GCP",
+						Optional:    true,
+						Computed:    true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+						Validators: []validator.String{
+							patternValidator{pattern: gcpProjectIDPattern, description: "6 to 30 lowercase letters, digits or hyphens, starting with a letter and not ending with a hyphen"},
+						},
 					},
 					"cloud_provider": schema.StringAttribute{
-						Description: "Name of cloud provider (e.g. AWS, GCP, Azure)",
-						Required:    true,
+						Description: "Name of cloud provider. One of AWS, Azure, GCP or OCI. Defaults to the default_cloud_provider of the provider",
+						Optional:    true,
+						Computed:    true,
+						Validators: []validator.String{
+							cloudProviderValidator{},
+						},
 					},
 					"role_arn": schema.StringAttribute{
-						Description: "Role ARN generated on the cloud provider",
+						Description: "Role ARN generated on the cloud provider, or the OCID of the dynamic group for OCI",
 						Required:    true,
 					},
 					"external_id": schema.StringAttribute{
 						Description: "External ID (UUID)",
 						Required:    true,
+						Sensitive:   true,
 					},
 					"region": schema.StringAttribute{
-						Description: "Region of the cloud provider",
+						Description: "Region of the cloud provider. For AWS accounts, must be a known AWS region such as us-east-1",
 						Optional:    true,
-						Default:     stringdefault.StaticString("us-east-1"),
+						Default:     stringdefault.StaticString(defaultAccountRegion),
 						Computed:    true,
 					},
+					"tags": schema.MapAttribute{
+						Description: "Tags attached to the account, such as team or cost center",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
 					"storage_class_name": schema.StringAttribute{
 						Description: "Storage class name of the cluster",
 						Optional:    true,
 						Default:     stringdefault.StaticString("ebs-sc"),
 						Computed:    true,
 					},
-					"products": schema.ListNestedAttribute{
-						Description: "List of products activated on the account",
+					"scan_coverage": schema.Float64Attribute{
+						Description: "Percentage of the account's resources visible to Zesty. Null when the account has not been scanned yet.",
+						Computed:    true,
+					},
+					"created_at": schema.StringAttribute{
+						Description: "Time the account was created (RFC3339)",
+						Computed:    true,
+					},
+					"updated_at": schema.StringAttribute{
+						Description: "Time the account was last updated (RFC3339)",
+						Computed:    true,
+					},
+					"additional_data": schema.StringAttribute{
+						Description: "JSON encoded additional data stored by Zesty for the account, without the roleARN and externalID exposed as role_arn and external_id, and without sensitive keys such as token, secret, password and apiKey at any depth. Fields not managed by this resource are left unchanged on update.",
+						Computed:    true,
+					},
+					"onboarding_status": schema.StringAttribute{
+						Description: "Onboarding status of the account as reported by Zesty",
+						Computed:    true,
+					},
+					"ready": schema.BoolAttribute{
+						Description: "Whether the account is fully onboarded, i.e. its onboarding status is one of the provider's ready_onboarding_statuses",
+						Computed:    true,
+					},
+					"products": schema.SetNestedAttribute{
+						Description: "Set of products activated on the account. Must not be empty unless the provider sets allow_empty_products",
 						Required:    true,
 						NestedObject: schema.NestedAttributeObject{
 							Attributes: map[string]schema.Attribute{
 								"name": schema.StringAttribute{
-									Description: "Name of product (e.g. Kompass)",
+									Description: "Name of product. One of Kompass, CM or ZestyDisk. ZestyDisk is only available on AWS",
 									Required:    true,
 								},
 								"active": schema.BoolAttribute{
@@ -102,7 +222,22 @@ func (r *AccountResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 									Required:    true,
 								},
 								"values": schema.StringAttribute{
-									Description: "Key-value pairs of product-specific values",
+									Description: "Key-value pairs of product-specific values, encoded as YAML or JSON (e.g. with jsonencode). When unset, the values managed by the Zesty API are left unchanged on update",
+									Optional:    true,
+									Computed:    true,
+								},
+								"values_map": schema.MapAttribute{
+									Description: "The values decoded by key, for use in expressions such as values_map[\"threshold\"]. Strings are kept as they are, and other values, including nested objects and lists, are encoded as JSON",
+									ElementType: types.StringType,
+									Computed:    true,
+								},
+								"values_hash": schema.StringAttribute{
+									Description: "SHA-256 checksum of the values, which only changes with their content, not with their encoding",
+									Computed:    true,
+								},
+								"region": schema.StringAttribute{
+									Description: "Region the product runs in, when it differs from the account region. Defaults to the account region.",
+									Optional:    true,
 									Computed:    true,
 								},
 							},
@@ -172,83 +307,170 @@ func (r *AccountResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.Client)
-
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
-			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected: *client.Client, got: %T.\nPlease report this issue to Zesty Support.", req.ProviderData),
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected: *providerData, got: %T.\nPlease report this issue to Zesty Support.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.valuesFormat = data.valuesFormat
+	r.defaultCloudProvider = data.defaultCloudProvider
+	r.allowEmptyProducts = data.allowEmptyProducts
+	r.readyStatuses = data.readyStatuses
+	r.tenants = data.tenants
 }
 
-func (r *AccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan accountResourceModel
-	diags := req.Plan.Get(ctx, &plan)
+// clientFor returns the client sending the requests of an account of the given tenant: the
+// provider's client when tenant is unset, or otherwise a copy of it sending the token of the tenant,
+// built on first use. It reports tenants the provider does not know.
+func (r *AccountResource) clientFor(tenant types.String) (*client.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if tenant.IsNull() || tenant.IsUnknown() || tenant.ValueString() == "" {
+		return r.client, diags
+	}
+
+	token, ok := r.tenants[tenant.ValueString()]
+	if !ok {
+		names := make([]string, 0, len(r.tenants))
+		for name := range r.tenants {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		diags.AddAttributeError(
+			path.Root("tenant"),
+			"Unknown Zesty Tenant",
+			fmt.Sprintf("The provider has no tenant %q. Add it to the tenants of the provider, which are: %s.", tenant.ValueString(), strings.Join(names, ", ")),
+		)
+		return nil, diags
+	}
+
+	if r.tenantClient == nil || r.tenantClient.Token != token {
+		r.tenantClient = r.client.WithToken(token)
+	}
+	return r.tenantClient, diags
+}
+
+// ModifyPlan derives the values_map and values_hash of each product from its planned values, so
+// they are known at plan time. It rejects empty products lists unless the provider allows them, and
+// sets the cloud provider of accounts that omit it to the default cloud provider of the provider.
+// The products check runs here rather than in a config validator, as validators run before the
+// provider is configured. For the same reason, the cloudProviderConfigValidators run again against
+// the default cloud provider.
+func (r *AccountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planValuesMaps(ctx, req, resp)
+	if resp.Diagnostics.HasError() || r.client == nil {
+		return
+	}
+
+	var tenant types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("tenant"), &tenant)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	_, diags := r.clientFor(tenant)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	payload := models.Payload{
-		AccountID:        plan.Account.ID.ValueString(),
-		Region:           plan.Account.Region.ValueStringPointer(),
-		CloudProvider:    models.CloudProvider(plan.Account.CloudProvider.ValueString()),
-		RoleARN:          plan.Account.RoleARN.ValueString(),
-		ExternalID:       plan.Account.ExternalID.ValueString(),
-		Products:         map[models.Product]models.ProductDetails{},
-		StorageClassName: plan.Account.StorageClassName.ValueString(),
-	}
-	for _, product := range plan.Account.Products {
-		payload.Products[models.Product(product.Name.ValueString())] = models.ProductDetails{
-			Active: product.Active.ValueBool(),
+	if !r.allowEmptyProducts {
+		var products types.Set
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, productsPath, &products)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !products.IsNull() && !products.IsUnknown() && len(products.Elements()) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				productsPath,
+				"Empty Products List",
+				"The account has no products, so no Zesty product would be activated on it. Add at least one product, or set allow_empty_products in the provider configuration if this is intended.",
+			)
+			return
 		}
 	}
 
-	if plan.Account.Cur != nil {
-		payload.Cur = &models.CurDetails{
-			S3Bucket:   plan.Account.Cur.S3Bucket.ValueString(),
-			ExportName: plan.Account.Cur.ExportName.ValueString(),
-			Type:       plan.Account.Cur.Type.ValueString(),
-		}
+	cloudProviderPath := path.Root("account").AtName("cloud_provider")
+	var cloudProvider types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, cloudProviderPath, &cloudProvider)...)
+	if resp.Diagnostics.HasError() || !cloudProvider.IsNull() {
+		return
 	}
 
-	if plan.Account.Athena != nil {
-		payload.Athena = &models.AthenaDetails{
-			AthenaDB:        plan.Account.Athena.AthenaDB.ValueString(),
-			AthenaS3Bucket:  plan.Account.Athena.AthenaS3Bucket.ValueString(),
-			AthenaProjectID: plan.Account.Athena.AthenaProjectID.ValueString(),
-			AthenaRegion:    plan.Account.Athena.AthenaRegion.ValueString(),
-			AthenaTable:     plan.Account.Athena.AthenaTable.ValueString(),
-			AthenaWorkgroup: plan.Account.Athena.AthenaWorkgroup.ValueString(),
-			AthenaCatalog:   plan.Account.Athena.AthenaCatalog.ValueString(),
-		}
+	if r.defaultCloudProvider == "" {
+		resp.Diagnostics.AddAttributeError(
+			cloudProviderPath,
+			"Missing Cloud Provider",
+			"The account has no cloud_provider and the provider has no default_cloud_provider. Set either of them.",
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, cloudProviderPath, types.StringValue(string(r.defaultCloudProvider)))...)
+	for _, v := range cloudProviderConfigValidators {
+		v.validateCloudProvider(ctx, req.Config, r.defaultCloudProvider, &resp.Diagnostics)
+	}
+}
+
+func (r *AccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan accountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	tflog.Info(ctx, "Sending create request", map[string]any{"payload": payload})
-	account, err := r.client.CreateAccount(payload)
+	timeout, diags := plan.Timeouts.Create(ctx, r.client.HTTPClient.Timeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payload, diags := toPayload(plan.Account)
+	resp.Diagnostics.Append(withAccountLabel(diags, accountLabel(plan.Account.ID, plan.Account.CloudProvider))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiClient, diags := r.clientFor(plan.Tenant)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Sending create request", map[string]any{"payload": loggablePayload(payload)})
+	account, err := apiClient.CreateAccount(ctx, payload)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating account",
-			"Could not create account, unexpected error: "+err.Error(),
+			fmt.Sprintf("Could not create %s, unexpected error: %s", accountLabel(plan.Account.ID, plan.Account.CloudProvider), err),
 		)
 		return
 	}
 
 	plan.ID = types.StringValue(account.AccountID)
-	model, diag := ToModel(account)
-	resp.Diagnostics.Append(diag...)
-	if diag != nil {
+	model, diag := ToModel(account, r.valuesFormat, r.readyStatuses)
+	resp.Diagnostics.Append(withAccountLabel(diag, accountLabel(types.StringValue(account.AccountID), types.StringValue(string(account.CloudProvider))))...)
+	if diag.HasError() {
 		return
 	}
 
+	model.OrganizationID = preserveOrganizationID(plan.Account.OrganizationID, model.OrganizationID)
+	keepProductAliases(plan.Account.Products, model.Products)
+	model.Products = keepInactiveProducts(plan.Account.Products, model.Products, model.Region, r.valuesFormat)
+	preserveValues(plan.Account.Products, model.Products)
+	model.Tags = preserveEmptyTags(plan.Account.Tags, model.Tags)
 	plan.Account = *model
-	tflog.Info(ctx, "Create result", map[string]any{"account": plan.Account})
-	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	tflog.Info(ctx, "Create result", map[string]any{"account": loggableAccount(plan.Account)})
+	plan.LastUpdated = lastUpdated(account)
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -259,30 +481,62 @@ func (r *AccountResource) Create(ctx context.Context, req resource.CreateRequest
 
 func (r *AccountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state accountResourceModel
-	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Read(ctx, r.client.HTTPClient.Timeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	apiClient, diags := r.clientFor(state.Tenant)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	tflog.Info(ctx, "Sending get request", map[string]any{"id": state.ID.ValueString()})
-	account, err := r.client.GetAccount(state.ID.ValueString())
+	var account *models.Account
+	var err error
+	if orgID := state.Account.OrganizationID; !orgID.IsNull() {
+		account, err = apiClient.GetAccountInOrg(ctx, orgID.ValueInt64(), state.ID.ValueString())
+	} else {
+		account, err = apiClient.GetAccount(ctx, state.ID.ValueString())
+	}
+	if err != nil && r.client.ReadOnly && client.IsNotFound(err) {
+		// Accounts created in read-only mode only exist in state, so the API does not know them.
+		resp.Diagnostics.AddWarning(
+			"Zesty Account Not Found in Read-Only Mode",
+			fmt.Sprintf("The Zesty API does not know %s. As the provider is read-only, it may only have been created in state, so the state is kept as it is.", accountLabel(state.ID, state.Account.CloudProvider)),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading Zesty Account",
-			"Could not read account ID "+state.ID.ValueString()+": "+err.Error(),
+			fmt.Sprintf("Could not read %s: %s", accountLabel(state.ID, state.Account.CloudProvider), err),
 		)
 		return
 	}
 
-	model, diag := ToModel(account)
-	resp.Diagnostics.Append(diag...)
-	if diag != nil {
+	model, diag := ToModel(account, r.valuesFormat, r.readyStatuses)
+	resp.Diagnostics.Append(withAccountLabel(diag, accountLabel(types.StringValue(account.AccountID), types.StringValue(string(account.CloudProvider))))...)
+	if diag.HasError() {
 		return
 	}
 
+	model.OrganizationID = preserveOrganizationID(state.Account.OrganizationID, model.OrganizationID)
+	keepProductAliases(state.Account.Products, model.Products)
+	model.Products = keepInactiveProducts(state.Account.Products, model.Products, model.Region, r.valuesFormat)
+	preserveValues(state.Account.Products, model.Products)
+	model.Tags = preserveEmptyTags(state.Account.Tags, model.Tags)
 	state.Account = *model
-	tflog.Info(ctx, "Read result", map[string]any{"account": state.Account})
+	tflog.Info(ctx, "Read result", map[string]any{"account": loggableAccount(state.Account)})
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -292,68 +546,77 @@ func (r *AccountResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 func (r *AccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan accountResourceModel
-	diags := req.Plan.Get(ctx, &plan)
+	var plan, state accountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Update(ctx, r.client.HTTPClient.Timeout)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	payload := models.Payload{
-		AccountID:        plan.Account.ID.ValueString(),
-		Region:           plan.Account.Region.ValueStringPointer(),
-		CloudProvider:    models.CloudProvider(plan.Account.CloudProvider.ValueString()),
-		RoleARN:          plan.Account.RoleARN.ValueString(),
-		ExternalID:       plan.Account.ExternalID.ValueString(),
-		Products:         map[models.Product]models.ProductDetails{},
-		StorageClassName: plan.Account.StorageClassName.ValueString(),
-	}
-	for _, product := range plan.Account.Products {
-		payload.Products[models.Product(product.Name.ValueString())] = models.ProductDetails{
-			Active: product.Active.ValueBool(),
-		}
+	payload, diags := toPayload(plan.Account)
+	resp.Diagnostics.Append(withAccountLabel(diags, accountLabel(plan.Account.ID, plan.Account.CloudProvider))...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if plan.Account.Cur != nil {
-		payload.Cur = &models.CurDetails{
-			S3Bucket:   plan.Account.Cur.S3Bucket.ValueString(),
-			ExportName: plan.Account.Cur.ExportName.ValueString(),
-			Type:       plan.Account.Cur.Type.ValueString(),
-		}
+	prior, diags := toPayload(state.Account)
+	resp.Diagnostics.Append(withAccountLabel(diags, accountLabel(state.Account.ID, state.Account.CloudProvider))...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	keepUnconfiguredValues(plan.Account.Products, payload.Products, prior.Products)
 
-	if plan.Account.Athena != nil {
-		payload.Athena = &models.AthenaDetails{
-			AthenaDB:        plan.Account.Athena.AthenaDB.ValueString(),
-			AthenaS3Bucket:  plan.Account.Athena.AthenaS3Bucket.ValueString(),
-			AthenaProjectID: plan.Account.Athena.AthenaProjectID.ValueString(),
-			AthenaRegion:    plan.Account.Athena.AthenaRegion.ValueString(),
-			AthenaTable:     plan.Account.Athena.AthenaTable.ValueString(),
-			AthenaWorkgroup: plan.Account.Athena.AthenaWorkgroup.ValueString(),
-			AthenaCatalog:   plan.Account.Athena.AthenaCatalog.ValueString(),
-		}
+	additionalData, err := unmanagedAdditionalData(state.Account.AdditionalData)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("account").AtName("additional_data"),
+			"Invalid additional data",
+			fmt.Sprintf("Could not parse the additional data of %s as JSON: %s", accountLabel(plan.Account.ID, plan.Account.CloudProvider), err),
+		)
+		return
 	}
+	payload.AdditionalData = additionalData
+	prior.AdditionalData = additionalData
 
-	tflog.Info(ctx, "Sending update request", map[string]any{"payload": payload})
-	updatedAccount, err := r.client.UpdateAccount(payload)
+	apiClient, diags := r.clientFor(plan.Tenant)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Sending update request", map[string]any{"payload": loggablePayload(payload)})
+	updatedAccount, err := apiClient.UpdateAccountPartial(ctx, prior, payload)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Updating Zesty Account",
-			"Could not update account, unexpected error: "+err.Error(),
+			fmt.Sprintf("Could not update %s, unexpected error: %s", accountLabel(plan.Account.ID, plan.Account.CloudProvider), err),
 		)
 		return
 	}
 
-	model, diag := ToModel(updatedAccount)
-	resp.Diagnostics.Append(diag...)
-	if diag != nil {
+	model, diag := ToModel(updatedAccount, r.valuesFormat, r.readyStatuses)
+	resp.Diagnostics.Append(withAccountLabel(diag, accountLabel(plan.Account.ID, plan.Account.CloudProvider))...)
+	if diag.HasError() {
 		return
 	}
 
+	model.OrganizationID = preserveOrganizationID(plan.Account.OrganizationID, model.OrganizationID)
+	keepProductAliases(plan.Account.Products, model.Products)
+	model.Products = keepInactiveProducts(plan.Account.Products, model.Products, model.Region, r.valuesFormat)
+	preserveValues(plan.Account.Products, model.Products)
+	model.Tags = preserveEmptyTags(plan.Account.Tags, model.Tags)
 	plan.ID = types.StringValue(model.ID.ValueString())
 	plan.Account = *model
-	tflog.Info(ctx, "Update result", map[string]any{"account": plan.Account})
-	plan.LastUpdated = types.StringValue(time.Now().Format(time.RFC850))
+	tflog.Info(ctx, "Update result", map[string]any{"account": loggableAccount(plan.Account)})
+	plan.LastUpdated = lastUpdated(updatedAccount)
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -364,46 +627,263 @@ func (r *AccountResource) Update(ctx context.Context, req resource.UpdateRequest
 
 func (r *AccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state accountResourceModel
-	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := state.Timeouts.Delete(ctx, r.client.HTTPClient.Timeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cloudProvider, diags := parseCloudProviderAttribute(state.Account.CloudProvider)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
 	payload := models.Payload{
 		AccountID:     state.Account.ID.ValueString(),
-		CloudProvider: models.CloudProvider(state.Account.CloudProvider.ValueString()),
+		CloudProvider: cloudProvider,
 		RoleARN:       state.Account.RoleARN.ValueString(),
 		ExternalID:    state.Account.ExternalID.ValueString(),
 	}
 
-	err := r.client.DeleteAccount(payload)
+	apiClient, diags := r.clientFor(state.Tenant)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := apiClient.DeleteAccountWithMode(ctx, payload, client.DeletionMode(state.DeletionMode.ValueString()))
+	if err != nil && state.ForceDestroy.ValueBool() && client.IsNotFound(err) {
+		tflog.Warn(ctx, "Zesty account already deleted", map[string]any{"id": state.ID.ValueString()})
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting account",
-			"Could not delete account, unexpected error: "+err.Error(),
+			fmt.Sprintf("Could not delete %s, unexpected error: %s", accountLabel(state.Account.ID, state.Account.CloudProvider), err),
 		)
 		return
 	}
+
+	if !state.VerifyDestroy.ValueBool() {
+		return
+	}
+	if err := apiClient.WaitForAccountDeleted(ctx, payload.AccountID); err != nil {
+		resp.Diagnostics.AddError(
+			"Error Verifying Account Deletion",
+			fmt.Sprintf("The Zesty API accepted the deletion of %s, but could not confirm it: %s", accountLabel(state.Account.ID, state.Account.CloudProvider), err),
+		)
+	}
+}
+
+// accountLabel identifies an account in diagnostics, e.g. `account "123456789012" (AWS)`, so
+// errors can be traced back to a resource in large configurations.
+func accountLabel(accountID types.String, cloudProvider types.String) string {
+	if cloudProvider.IsNull() || cloudProvider.IsUnknown() || cloudProvider.ValueString() == "" {
+		return fmt.Sprintf("account %q", accountID.ValueString())
+	}
+	return fmt.Sprintf("account %q (%s)", accountID.ValueString(), cloudProvider.ValueString())
+}
+
+// withAccountLabel prefixes the detail of each diagnostic in diags with label, keeping the
+// attribute path of attribute errors.
+func withAccountLabel(diags diag.Diagnostics, label string) diag.Diagnostics {
+	if diags == nil {
+		return nil
+	}
+
+	labeled := make(diag.Diagnostics, 0, len(diags))
+	for _, d := range diags {
+		detail := fmt.Sprintf("%s: %s", label, d.Detail())
+		withPath, hasPath := d.(diag.DiagnosticWithPath)
+		switch {
+		case d.Severity() == diag.SeverityError && hasPath:
+			labeled = append(labeled, diag.NewAttributeErrorDiagnostic(withPath.Path(), d.Summary(), detail))
+		case d.Severity() == diag.SeverityError:
+			labeled = append(labeled, diag.NewErrorDiagnostic(d.Summary(), detail))
+		default:
+			labeled = append(labeled, diag.NewWarningDiagnostic(d.Summary(), detail))
+		}
+	}
+	return labeled
+}
+
+// planValuesMaps sets the values_map and values_hash of each planned product with known values.
+func planValuesMaps(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var set types.Set
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, productsPath, &set)...)
+	if resp.Diagnostics.HasError() || set.IsNull() || set.IsUnknown() {
+		return
+	}
+	for _, element := range set.Elements() {
+		if element.IsUnknown() {
+			return
+		}
+	}
+
+	var products []productModel
+	resp.Diagnostics.Append(set.ElementsAs(ctx, &products, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for i, product := range products {
+		if product.Values.IsUnknown() {
+			continue
+		}
+		products[i].ValuesMap = valuesMapValue(product.Values)
+		products[i].ValuesHash = valuesHashValue(product.Values)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, productsPath, products)...)
+}
+
+// accountImportID is a parsed import ID. Composite IDs of the form org_id/cloud_provider/account_id
+// set OrganizationID and CloudProvider; a bare account ID leaves them empty. Either form may be
+// prefixed with tenant: to set Tenant.
+type accountImportID struct {
+	Tenant         string
+	OrganizationID int64
+	CloudProvider  models.CloudProvider
+	AccountID      string
+}
+
+// parseImportID parses an import ID, accepting either org_id/cloud_provider/account_id or a bare
+// account ID, optionally prefixed with the name of a tenant and a colon.
+func parseImportID(id string) (accountImportID, error) {
+	tenant, rest, found := strings.Cut(id, ":")
+	if !found {
+		return parseAccountImportID(id)
+	}
+	if tenant == "" {
+		return accountImportID{}, fmt.Errorf("tenant must not be empty in %q", id)
+	}
+
+	importID, err := parseAccountImportID(rest)
+	if err != nil {
+		return accountImportID{}, err
+	}
+	importID.Tenant = tenant
+	return importID, nil
+}
+
+// parseAccountImportID parses an import ID without a tenant.
+func parseAccountImportID(id string) (accountImportID, error) {
+	if !strings.Contains(id, "/") {
+		return accountImportID{AccountID: id}, nil
+	}
+
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 {
+		return accountImportID{}, fmt.Errorf("expected org_id/cloud_provider/account_id, got %q", id)
+	}
+
+	orgID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || orgID <= 0 {
+		return accountImportID{}, fmt.Errorf("organization ID %q must be a positive integer", parts[0])
+	}
+
+	cloudProvider, err := models.ParseCloudProvider(parts[1])
+	if err != nil {
+		return accountImportID{}, err
+	}
+
+	if parts[2] == "" {
+		return accountImportID{}, fmt.Errorf("account ID must not be empty in %q", id)
+	}
+
+	return accountImportID{
+		OrganizationID: orgID,
+		CloudProvider:  cloudProvider,
+		AccountID:      parts[2],
+	}, nil
+}
+
+// FormatImportID returns the composite import ID of an account, in the org_id/cloud_provider/account_id
+// form accepted by parseImportID.
+func FormatImportID(orgID int64, cloudProvider models.CloudProvider, accountID string) string {
+	return fmt.Sprintf("%d/%s/%s", orgID, cloudProvider, accountID)
+}
+
+// ImportIDsByOrg lists the composite import IDs of every account in the organization orgID, sorted,
+// so tooling can generate terraform import commands or import blocks for an existing organization.
+func ImportIDsByOrg(ctx context.Context, c *client.Client, orgID int64) ([]string, error) {
+	accounts, err := c.GetAccounts(ctx, client.AccountsFilter{OrganizationID: orgID})
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts of organization %d: %w", orgID, err)
+	}
+
+	ids := make([]string, 0, len(*accounts))
+	for _, account := range *accounts {
+		ids = append(ids, FormatImportID(orgID, account.CloudProvider, account.AccountID))
+	}
+	sort.Strings(ids)
+	return ids, nil
 }
 
 func (r *AccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id := req.ID
+	importID, err := parseImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Could not parse import ID: %s", err),
+		)
+		return
+	}
+
+	id := importID.AccountID
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_destroy"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("verify_destroy"), false)...)
+
+	tenant := types.StringNull()
+	if importID.Tenant != "" {
+		tenant = types.StringValue(importID.Tenant)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tenant"), tenant)...)
 
-	account, err := r.client.GetAccount(id)
+	apiClient, diags := r.clientFor(tenant)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var account *models.Account
+	if importID.OrganizationID != 0 {
+		account, err = apiClient.GetAccountInOrg(ctx, importID.OrganizationID, id)
+	} else {
+		account, err = apiClient.GetAccount(ctx, id)
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error importing resource",
-			fmt.Sprintf("Could not read resource with ID %q: %s", id, err),
+			fmt.Sprintf("Could not read %s: %s", accountLabel(types.StringValue(id), types.StringValue(string(importID.CloudProvider))), err),
 		)
 		return
 	}
 
-	model, diag := ToModel(account)
-	resp.Diagnostics.Append(diag...)
-	if diag != nil {
+	if importID.CloudProvider != "" && account.CloudProvider != importID.CloudProvider {
+		resp.Diagnostics.AddError(
+			"Error importing resource",
+			fmt.Sprintf("Account %q belongs to cloud provider %s, not %s", id, account.CloudProvider, importID.CloudProvider),
+		)
 		return
 	}
 
+	model, diag := toModelBestEffort(account, r.valuesFormat, r.readyStatuses)
+	resp.Diagnostics.Append(withAccountLabel(diag, accountLabel(types.StringValue(account.AccountID), types.StringValue(string(account.CloudProvider))))...)
+	if diag.HasError() {
+		return
+	}
+	if model.OrganizationID.IsNull() && importID.OrganizationID != 0 {
+		model.OrganizationID = types.Int64Value(importID.OrganizationID)
+	}
+
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("account"), model)...)
 }
@@ -0,0 +1,1804 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+	"github.com/zesty-co/terraform-provider-zesty/internal/provider"
+)
+
+func testAccAccountResourceConfig(server *httptest.Server, accountID string) string {
+	return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "zesty_account" "test" {
+  account = {
+    id             = %q
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`, accountID)
+}
+
+func TestAccAccountResource_AccountIDRequiresReplace(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceConfig(server, "123456789012"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "id", "123456789012"),
+					resource.TestCheckResourceAttr("zesty_account.test", "account.id", "123456789012"),
+				),
+			},
+			{
+				Config: testAccAccountResourceConfig(server, "210987654321"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("zesty_account.test", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "id", "210987654321"),
+					resource.TestCheckResourceAttr("zesty_account.test", "account.id", "210987654321"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ProductValues(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [
+      {
+        name   = "CM"
+        active = true
+      },
+      {
+        name   = "Kompass"
+        active = true
+        values = jsonencode({ threshold = 80 })
+      },
+    ]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{
+						"name":                 "Kompass",
+						"values":               `{"threshold":80}`,
+						"values_map.threshold": "80",
+					}),
+					func(_ *terraform.State) error {
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						values := api.accounts["123456789012"].Products[models.Kompass].Values
+						if values["threshold"] != float64(80) {
+							return fmt.Errorf("expected Kompass threshold 80 in payload, got %v", values)
+						}
+						if len(api.accounts["123456789012"].Products[models.CM].Values) != 0 {
+							return fmt.Errorf("expected no CM values in payload")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_UnknownProductName(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Komppass"
+      active = true
+    }]
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Product "Komppass" is not supported`),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_DeprecatedProductName(t *testing.T) {
+	original := models.ProductAliases
+	models.ProductAliases = map[models.Product]models.Product{"CostManagement": models.CM}
+	t.Cleanup(func() { models.ProductAliases = original })
+
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "CostManagement"
+      active = true
+    }]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "CostManagement"}),
+					func(_ *terraform.State) error {
+						if _, ok := api.accounts["123456789012"].Products[models.CM]; !ok {
+							return fmt.Errorf("expected CM in payload, got %v", api.accounts["123456789012"].Products)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_DuplicateProduct(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [
+      {
+        name   = "Kompass"
+        active = true
+      },
+      {
+        name   = "Kompass"
+        active = false
+      },
+    ]
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Product "Kompass" is configured more than once`),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_AdditionalDataRoundTrip(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceConfig(server, "123456789012"),
+			},
+			{
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.accounts["123456789012"].AdditionalData["diskConfig"] = "managed-by-zesty"
+				},
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = false
+    }]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "Kompass", "active": "false"}),
+					func(_ *terraform.State) error {
+						if value := api.accounts["123456789012"].AdditionalData["diskConfig"]; value != "managed-by-zesty" {
+							return fmt.Errorf("expected diskConfig to survive the update, got %v", value)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_OCI(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "ocid1.tenancy.oc1..aaaaaaaaexample"
+    cloud_provider = "OCI"
+    role_arn       = "ocid1.dynamicgroup.oc1..aaaaaaaaexample"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+
+data "zesty_accounts" "oci" {
+  cloud_provider = "OCI"
+  depends_on     = [zesty_account.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.cloud_provider", "OCI"),
+					resource.TestCheckResourceAttr("zesty_account.test", "account.role_arn", "ocid1.dynamicgroup.oc1..aaaaaaaaexample"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.oci", "accounts.#", "1"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.oci", "accounts.0.cloud_provider", "OCI"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_UnknownCloudProvider(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "Oracle"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`cloud\s+provider\s+"Oracle"\s+is\s+not\s+supported`),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "aws"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`did\s+you\s+mean\s+"AWS"\?`),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_LastUpdatedFromServer(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.updatedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("IDT", 3*60*60))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceConfig(server, "123456789012"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "last_updated", "2024-05-01T09:00:00Z"),
+					resource.TestCheckResourceAttr("zesty_account.test", "account.updated_at", "2024-05-01T09:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func testAccProductOrderConfig(server *httptest.Server, products ...string) string {
+	var blocks strings.Builder
+	for _, product := range products {
+		fmt.Fprintf(&blocks, `
+      {
+        name   = %q
+        active = %t
+      },`, product, product != "CM")
+	}
+
+	return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [%s
+    ]
+  }
+}
+`, blocks.String())
+}
+
+func TestAccAccountResource_ProductOrder(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProductOrderConfig(server, "ZestyDisk", "Kompass", "CM"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.products.#", "3"),
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "ZestyDisk", "active": "true"}),
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "Kompass", "active": "true"}),
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "CM", "active": "false"}),
+				),
+			},
+			{
+				// Products are a set, so reordering them in the configuration plans no changes.
+				Config: testAccProductOrderConfig(server, "CM", "ZestyDisk", "Kompass"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_OrganizationID(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id              = "123456789012"
+    organization_id = 42
+    cloud_provider  = "AWS"
+    role_arn        = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id     = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+
+data "zesty_accounts" "org" {
+  organization_id = 42
+  depends_on      = [zesty_account.test]
+}
+
+data "zesty_accounts" "other_org" {
+  organization_id = 7
+  depends_on      = [zesty_account.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.organization_id", "42"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.org", "accounts.#", "1"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.org", "accounts.0.organization_id", "42"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.other_org", "accounts.#", "0"),
+					func(_ *terraform.State) error {
+						if orgID := api.accounts["123456789012"].OrganizationID; orgID != 42 {
+							return fmt.Errorf("expected organization 42 in payload, got %d", orgID)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_OrganizationIDFromAPI(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceConfig(server, "123456789012"),
+				Check:  resource.TestCheckNoResourceAttr("zesty_account.test", "account.organization_id"),
+			},
+			{
+				// The organization reported by the API shows in state without replacing the account.
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					account := api.accounts["123456789012"]
+					account.OrganizationID = 42
+					api.accounts["123456789012"] = account
+				},
+				Config: testAccAccountResourceConfig(server, "123456789012"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("zesty_account.test", plancheck.ResourceActionNoop),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("zesty_account.test", "account.organization_id", "42"),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ImportLegacyID(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceConfig(server, "123456789012"),
+			},
+			{
+				Config:                  testAccAccountResourceConfig(server, "123456789012"),
+				ResourceName:            "zesty_account.test",
+				ImportState:             true,
+				ImportStateId:           "123456789012",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ImportCompositeID(t *testing.T) {
+	_, server := newTestAPI(t)
+	config := testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id              = "123456789012"
+    organization_id = 42
+    cloud_provider  = "AWS"
+    role_arn        = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id     = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config:                  config,
+				ResourceName:            "zesty_account.test",
+				ImportState:             true,
+				ImportStateId:           "42/AWS/123456789012",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+			},
+			{
+				Config:        config,
+				ResourceName:  "zesty_account.test",
+				ImportState:   true,
+				ImportStateId: "7/AWS/123456789012",
+				ExpectError:   regexp.MustCompile(`Error importing resource`),
+			},
+			{
+				Config:        config,
+				ResourceName:  "zesty_account.test",
+				ImportState:   true,
+				ImportStateId: "42/GCP/123456789012",
+				ExpectError:   regexp.MustCompile(`belongs to cloud provider AWS`),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ImportIncomplete(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.accounts["123456789012"] = models.Account{
+		AccountID:     "123456789012",
+		CloudProvider: models.AWS,
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {Active: true, Values: map[string]any{"threshold": float64(80)}},
+		},
+		AdditionalData: map[string]any{"externalID": 42},
+	}
+	config := testAccAccountResourceConfig(server, "123456789012")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config,
+				ResourceName:       "zesty_account.test",
+				ImportState:        true,
+				ImportStateId:      "123456789012",
+				ImportStatePersist: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported resource, got %d", len(states))
+					}
+					attributes := states[0].Attributes
+					expected := map[string]string{
+						"id":                              "123456789012",
+						"account.role_arn":                "",
+						"account.external_id":             "",
+						"account.products.#":              "1",
+						"account.products.0.name":         "Kompass",
+						"account.products.0.values":       "threshold: 80\n",
+						"account.products.0.values_map.%": "1",
+					}
+					for key, value := range expected {
+						if attributes[key] != value {
+							return fmt.Errorf("expected %s to be %q, got %q", key, value, attributes[key])
+						}
+					}
+					return nil
+				},
+			},
+			{
+				// Applying the configuration stores the missing fields on the account.
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("zesty_account.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.role_arn", "arn:aws:iam::123456789012:role/ZestyIamRole"),
+					resource.TestCheckResourceAttr("zesty_account.test", "account.external_id", "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"),
+					func(*terraform.State) error {
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						account := api.accounts["123456789012"]
+						if account.AdditionalData["roleARN"] != "arn:aws:iam::123456789012:role/ZestyIamRole" {
+							return fmt.Errorf("role ARN not stored on the account: %v", account.AdditionalData)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ImportMalformedID(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	for _, id := range []string{"42/AWS", "org/AWS/123456789012", "42/Oracle/123456789012", "42/aws/123456789012", "42/AWS/"} {
+		t.Run(id, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config:        testAccAccountResourceConfig(server, "123456789012"),
+						ResourceName:  "zesty_account.test",
+						ImportState:   true,
+						ImportStateId: id,
+						ExpectError:   regexp.MustCompile(`Invalid import ID`),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccAccountResource_DeactivateProduct(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.omitInactive = true
+	config := func(active bool) string {
+		return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [
+      {
+        name   = "Kompass"
+        active = true
+      },
+      {
+        name   = "CM"
+        active = %t
+      },
+    ]
+  }
+}
+`, active)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(true),
+				Check:  resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "CM", "active": "true"}),
+			},
+			{
+				Config: config(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.products.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "CM", "active": "false"}),
+					func(_ *terraform.State) error {
+						details, ok := api.lastPayload.Products[models.CM]
+						if !ok || details.Active {
+							return fmt.Errorf("expected CM to be sent as inactive, got %+v", api.lastPayload.Products)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   config(false),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ForceDestroy(t *testing.T) {
+	api, server := newTestAPI(t)
+	config := testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  force_destroy = true
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "force_destroy", "true"),
+					func(_ *terraform.State) error {
+						// The account disappears server-side before destroy.
+						api.deleteStatus = http.StatusNotFound
+						return nil
+					},
+				),
+			},
+			{
+				Config:  config,
+				Destroy: true,
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_VerifyDestroy(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.deleteLingers = 2
+	config := testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  verify_destroy = true
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("zesty_account.test", "verify_destroy", "true"),
+			},
+			{
+				// The account is still readable for two polls after the delete, then disappears.
+				Config:  config,
+				Destroy: true,
+				Check: func(_ *terraform.State) error {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					if _, ok := api.accounts["123456789012"]; ok {
+						return fmt.Errorf("expected the account to be deleted")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccDeletionModeConfig(server *httptest.Server, deletionMode string) string {
+	return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "zesty_account" "test" {
+  deletion_mode = %s
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`, deletionMode)
+}
+
+func TestAccAccountResource_DeletionMode(t *testing.T) {
+	for _, tt := range []struct {
+		deletionMode string
+		expected     string
+	}{
+		{deletionMode: "null", expected: ""},
+		{deletionMode: `"soft"`, expected: "soft"},
+		{deletionMode: `"hard"`, expected: "hard"},
+	} {
+		t.Run(tt.deletionMode, func(t *testing.T) {
+			api, server := newTestAPI(t)
+
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: testAccDeletionModeConfig(server, tt.deletionMode),
+					},
+				},
+				CheckDestroy: func(_ *terraform.State) error {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					if len(api.deletionModes) != 1 || api.deletionModes[0] != tt.expected {
+						return fmt.Errorf("expected one delete request with mode %q, got %q", tt.expected, api.deletionModes)
+					}
+					return nil
+				},
+			})
+		})
+	}
+}
+
+func TestAccAccountResource_DeletionModeInvalid(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDeletionModeConfig(server, `"purge"`),
+				ExpectError: regexp.MustCompile(`Value "purge" is not supported`),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_CreateTimeout(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.createDelay = time.Minute
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  timeouts = {
+    create = "1s"
+  }
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Error\s+creating\s+account[\s\S]*context\s+deadline\s+exceeded`),
+			},
+		},
+	})
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	assert.Empty(t, api.accounts)
+}
+
+func TestAccAccountResource_VerifyDestroyTimeout(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.deleteLingers = 1000
+	config := testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  verify_destroy = true
+  timeouts = {
+    delete = "2s"
+  }
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config:      config,
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Error\s+Verifying\s+Account\s+Deletion`),
+			},
+			{
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.deleteLingers = 0
+					delete(api.lingering, "123456789012")
+				},
+				Config: config,
+			},
+		},
+	})
+}
+
+// testAccTenantsProviderConfig configures the provider with the tenants tenant_b and tenant_c.
+func testAccTenantsProviderConfig(server *httptest.Server) string {
+	return fmt.Sprintf(`
+provider "zesty" {
+  host  = %q
+  token = "test-token"
+  tenants = {
+    tenant_b = "tenant-b-token"
+    tenant_c = "tenant-c-token"
+  }
+}
+`, server.URL)
+}
+
+// testAccTenantAccountConfig is an account of tenant.
+func testAccTenantAccountConfig(tenant string) string {
+	return fmt.Sprintf(`
+resource "zesty_account" "test" {
+  tenant = %q
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`, tenant)
+}
+
+func TestAccAccountResource_Tenant(t *testing.T) {
+	api, server := newTestAPI(t)
+	expectTokens := func(expected map[string]string) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			for method, token := range expected {
+				if api.tokens[method] != token {
+					return fmt.Errorf("expected %s requests to send token %q, got %q", method, token, api.tokens[method])
+				}
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if api.tokens[http.MethodDelete] != "tenant-c-token" {
+				return fmt.Errorf("expected the delete request to send token %q, got %q", "tenant-c-token", api.tokens[http.MethodDelete])
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccTenantsProviderConfig(server) + testAccTenantAccountConfig("tenant_a"),
+				ExpectError: regexp.MustCompile(`Unknown Zesty Tenant`),
+			},
+			{
+				Config: testAccTenantsProviderConfig(server) + testAccTenantAccountConfig("tenant_b"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "tenant", "tenant_b"),
+					expectTokens(map[string]string{
+						http.MethodPost: "tenant-b-token",
+						http.MethodGet:  "tenant-b-token",
+					}),
+				),
+			},
+			{
+				// The token of the new tenant is used from the update on.
+				Config: testAccTenantsProviderConfig(server) + testAccTenantAccountConfig("tenant_c"),
+				Check: expectTokens(map[string]string{
+					http.MethodPatch: "tenant-c-token",
+					http.MethodGet:   "tenant-c-token",
+				}),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ImportTenant(t *testing.T) {
+	api, server := newTestAPI(t)
+	config := testAccTenantsProviderConfig(server) + testAccTenantAccountConfig("tenant_b")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					delete(api.tokens, http.MethodGet)
+				},
+				Config:                  config,
+				ResourceName:            "zesty_account.test",
+				ImportState:             true,
+				ImportStateId:           "tenant_b:123456789012",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"last_updated"},
+				ImportStateCheck: func(_ []*terraform.InstanceState) error {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					if token := api.tokens[http.MethodGet]; token != "tenant-b-token" {
+						return fmt.Errorf("expected the import to read the account with token %q, got %q", "tenant-b-token", token)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_EmptyProducts(t *testing.T) {
+	api, server := newTestAPI(t)
+	resourceConfig := `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products       = []
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(server) + resourceConfig,
+				ExpectError: regexp.MustCompile(`Empty\s+Products\s+List`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host                 = %q
+  token                = "test-token"
+  allow_empty_products = true
+}
+`, server.URL) + resourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.products.#", "0"),
+					func(_ *terraform.State) error {
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						if _, ok := api.accounts["123456789012"]; !ok {
+							return fmt.Errorf("expected the account to be created")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_DeleteNotFound(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceConfig(server, "123456789012"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "force_destroy", "false"),
+					func(_ *terraform.State) error {
+						api.deleteStatus = http.StatusNotFound
+						return nil
+					},
+				),
+			},
+			{
+				Config:      testAccAccountResourceConfig(server, "123456789012"),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Could\s+not\s+delete\s+account\s+"123456789012"\s+\(AWS\)`),
+			},
+			{
+				PreConfig: func() { api.deleteStatus = 0 },
+				Config:    testAccAccountResourceConfig(server, "123456789012"),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ProductRegion(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    region         = "us-east-1"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [
+      {
+        name   = "Kompass"
+        active = true
+      },
+      {
+        name   = "ZestyDisk"
+        active = true
+        region = "eu-west-1"
+      },
+    ]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "Kompass", "region": "us-east-1"}),
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "ZestyDisk", "region": "eu-west-1"}),
+					func(_ *terraform.State) error {
+						products := api.lastPayload.Products
+						if region := products[models.Kompass].Region; region != nil {
+							return fmt.Errorf("expected Kompass to use the account region, got %q", *region)
+						}
+						if region := products[models.ZestyDisk].Region; region == nil || *region != "eu-west-1" {
+							return fmt.Errorf("expected ZestyDisk region eu-west-1, got %v", region)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ReadOnly(t *testing.T) {
+	api, server := newTestAPI(t)
+	config := func(readOnly bool, active bool) string {
+		return fmt.Sprintf(`
+provider "zesty" {
+  host      = %q
+  token     = "test-token"
+  read_only = %t
+}
+
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = %t
+    }]
+  }
+}
+`, server.URL, readOnly, active)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if _, ok := api.accounts["123456789012"]; !ok {
+				return fmt.Errorf("expected the account to survive destroy in read-only mode")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(false, true),
+				Check: func(_ *terraform.State) error {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.mutations = 0
+					return nil
+				},
+			},
+			{
+				// The update is only echoed into state, so the next refresh shows the drift.
+				Config:             config(true, false),
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("zesty_account.test", "account.products.*", map[string]string{"name": "Kompass", "active": "false"}),
+					func(_ *terraform.State) error {
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						if api.mutations != 0 {
+							return fmt.Errorf("expected no mutating requests, got %d", api.mutations)
+						}
+						if !api.accounts["123456789012"].Products[models.Kompass].Active {
+							return fmt.Errorf("expected Kompass to stay active server-side")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ReadOnlyCreate(t *testing.T) {
+	api, server := newTestAPI(t)
+	checkUntouched := func(_ *terraform.State) error {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		if api.mutations != 0 {
+			return fmt.Errorf("expected no mutating requests, got %d", api.mutations)
+		}
+		if len(api.accounts) != 0 {
+			return fmt.Errorf("expected no account server-side, got %v", api.accounts)
+		}
+		return nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkUntouched,
+		Steps: []resource.TestStep{
+			{
+				// The account only exists in state, so refreshing it keeps it rather than failing,
+				// and the plan after apply is empty.
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host      = %q
+  token     = "test-token"
+  read_only = true
+}
+
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "id", "123456789012"),
+					checkUntouched,
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ReadErrorIncludesAccount(t *testing.T) {
+	api, server := newTestAPI(t)
+	var removed models.Account
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceConfig(server, "123456789012"),
+			},
+			{
+				PreConfig: func() {
+					removed = api.accounts["123456789012"]
+					delete(api.accounts, "123456789012")
+				},
+				Config:      testAccAccountResourceConfig(server, "123456789012"),
+				ExpectError: regexp.MustCompile(`Could\s+not\s+read\s+account\s+"123456789012"\s+\(AWS\)`),
+			},
+			{
+				PreConfig: func() { api.accounts["123456789012"] = removed },
+				Config:    testAccAccountResourceConfig(server, "123456789012"),
+			},
+		},
+	})
+}
+
+func testAccAzureAccountConfig(server *httptest.Server, cloudProvider string, subscriptionID string) string {
+	return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "zesty_account" "test" {
+  account = {
+    id              = "azure-account"
+    cloud_provider  = %q
+    subscription_id = %q
+    role_arn        = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/zesty"
+    external_id     = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`, cloudProvider, subscriptionID)
+}
+
+func TestAccAccountResource_SubscriptionID(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureAccountConfig(server, "Azure", "6f1c2a9e-4b7d-4c1e-9a3f-2d8e5b7c1a04"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.subscription_id", "6f1c2a9e-4b7d-4c1e-9a3f-2d8e5b7c1a04"),
+					func(_ *terraform.State) error {
+						if id := api.lastPayload.SubscriptionID; id != "6f1c2a9e-4b7d-4c1e-9a3f-2d8e5b7c1a04" {
+							return fmt.Errorf("expected subscription ID in payload, got %q", id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_SubscriptionIDNotAzure(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceConfig(server, "123456789012"),
+				Check:  resource.TestCheckNoResourceAttr("zesty_account.test", "account.subscription_id"),
+			},
+			{
+				Config:      testAccAzureAccountConfig(server, "AWS", "6f1c2a9e-4b7d-4c1e-9a3f-2d8e5b7c1a04"),
+				ExpectError: regexp.MustCompile(`subscription_id\s+can\s+only\s+be\s+set\s+for\s+Azure`),
+			},
+			{
+				Config:      testAccAzureAccountConfig(server, "Azure", "not-a-guid"),
+				ExpectError: regexp.MustCompile(`Value\s+"not-a-guid"\s+is\s+not\s+valid`),
+			},
+		},
+	})
+}
+
+func testAccGCPAccountConfig(server *httptest.Server, cloudProvider string, projectID string) string {
+	return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "zesty_account" "test" {
+  account = {
+    id             = "gcp-account"
+    cloud_provider = %q
+    project_id     = %q
+    role_arn       = "zesty@zesty-prod-123.iam.gserviceaccount.com"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`, cloudProvider, projectID)
+}
+
+func TestAccAccountResource_ProjectID(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGCPAccountConfig(server, "GCP", "zesty-prod-123"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.project_id", "zesty-prod-123"),
+					resource.TestCheckNoResourceAttr("zesty_account.test", "account.subscription_id"),
+					func(_ *terraform.State) error {
+						if id := api.lastPayload.ProjectID; id != "zesty-prod-123" {
+							return fmt.Errorf("expected project ID in payload, got %q", id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_ProjectIDInvalid(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccGCPAccountConfig(server, "AWS", "zesty-prod-123"),
+				ExpectError: regexp.MustCompile(`project_id\s+can\s+only\s+be\s+set\s+for\s+GCP`),
+			},
+			{
+				Config:      testAccGCPAccountConfig(server, "GCP", "Zesty_Prod"),
+				ExpectError: regexp.MustCompile(`Value\s+"Zesty_Prod"\s+is\s+not\s+valid`),
+			},
+			{
+				Config:      testAccGCPAccountConfig(server, "GCP", "short"),
+				ExpectError: regexp.MustCompile(`Value\s+"short"\s+is\s+not\s+valid`),
+			},
+		},
+	})
+}
+
+func testAccAccountResourceTagsConfig(server *httptest.Server, tags string) string {
+	return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    tags           = %s
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`, tags)
+}
+
+func TestAccAccountResource_Tags(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceTagsConfig(server, `{ team = "platform", cost-center = "1234" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.tags.%", "2"),
+					resource.TestCheckResourceAttr("zesty_account.test", "account.tags.team", "platform"),
+					resource.TestCheckResourceAttr("zesty_account.test", "account.tags.cost-center", "1234"),
+					func(_ *terraform.State) error {
+						if tags := api.lastPayload.Tags; tags["team"] != "platform" || tags["cost-center"] != "1234" {
+							return fmt.Errorf("expected tags in payload, got %v", tags)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccAccountResourceTagsConfig(server, `{ team = "data" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.tags.%", "1"),
+					resource.TestCheckResourceAttr("zesty_account.test", "account.tags.team", "data"),
+					func(_ *terraform.State) error {
+						if tags := api.lastPayload.Tags; len(tags) != 1 || tags["team"] != "data" {
+							return fmt.Errorf("expected updated tags in payload, got %v", tags)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccAccountResourceTagsConfig(server, `{}`),
+				Check:  resource.TestCheckResourceAttr("zesty_account.test", "account.tags.%", "0"),
+			},
+		},
+	})
+}
+
+func TestImportIDsByOrg(t *testing.T) {
+	api, server := newTestAPI(t)
+	for _, account := range []models.Account{
+		{AccountID: "210987654321", CloudProvider: models.AWS, OrganizationID: 42},
+		{AccountID: "azure-account", CloudProvider: models.Azure, OrganizationID: 42},
+		{AccountID: "123456789012", CloudProvider: models.AWS, OrganizationID: 42},
+		{AccountID: "other-org", CloudProvider: models.GCP, OrganizationID: 7},
+	} {
+		api.accounts[account.AccountID] = account
+	}
+
+	c, err := client.NewClient(&server.URL, "test-token")
+	require.NoError(t, err)
+
+	ids, err := provider.ImportIDsByOrg(context.Background(), c, 42)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"42/AWS/123456789012",
+		"42/AWS/210987654321",
+		"42/Azure/azure-account",
+	}, ids)
+
+	ids, err = provider.ImportIDsByOrg(context.Background(), c, 1)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestImportIDsByOrg_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c, err := client.NewClient(&server.URL, "test-token")
+	require.NoError(t, err)
+
+	_, err = provider.ImportIDsByOrg(context.Background(), c, 42)
+	assert.ErrorContains(t, err, "listing accounts of organization 42")
+}
+
+func TestAccAccountResource_ProductNotSupportedOnCloudProvider(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "azure-account"
+    cloud_provider = "Azure"
+    role_arn       = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/zesty"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [
+      {
+        name   = "Kompass"
+        active = true
+      },
+      {
+        name   = "ZestyDisk"
+        active = true
+      },
+    ]
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`ZestyDisk\s+is\s+not\s+supported\s+on\s+Azure`),
+			},
+		},
+	})
+}
+
+func testAccDefaultCloudProviderConfig(server *httptest.Server, defaultCloudProvider string, cloudProvider string) string {
+	providerConfig := fmt.Sprintf(`
+provider "zesty" {
+  host                   = %q
+  token                  = "test-token"
+  default_cloud_provider = %q
+}
+`, server.URL, defaultCloudProvider)
+	if defaultCloudProvider == "" {
+		providerConfig = testAccProviderConfig(server)
+	}
+
+	cloudProviderConfig := ""
+	if cloudProvider != "" {
+		cloudProviderConfig = fmt.Sprintf("cloud_provider = %q", cloudProvider)
+	}
+
+	return providerConfig + fmt.Sprintf(`
+resource "zesty_account" "test" {
+  account = {
+    id          = "123456789012"
+    %s
+    role_arn    = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`, cloudProviderConfig)
+}
+
+func TestAccAccountResource_DefaultCloudProvider(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	expectPayloadCloudProvider := func(expected models.CloudProvider) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if api.lastPayload.CloudProvider != expected {
+				return fmt.Errorf("expected cloud provider %s in payload, got %s", expected, api.lastPayload.CloudProvider)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The default applies when cloud_provider is omitted.
+				Config: testAccDefaultCloudProviderConfig(server, "AWS", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.cloud_provider", "AWS"),
+					expectPayloadCloudProvider(models.AWS),
+				),
+			},
+			{
+				// The default is stable across plans.
+				Config:             testAccDefaultCloudProviderConfig(server, "AWS", ""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				// An explicit cloud_provider overrides the default.
+				Config: testAccDefaultCloudProviderConfig(server, "Azure", "AWS"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.cloud_provider", "AWS"),
+					expectPayloadCloudProvider(models.AWS),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_DefaultCloudProviderErrors(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDefaultCloudProviderConfig(server, "", ""),
+				ExpectError: regexp.MustCompile(`has\s+no\s+cloud_provider\s+and\s+the\s+provider\s+has\s+no\s+default_cloud_provider`),
+			},
+			{
+				Config:      testAccDefaultCloudProviderConfig(server, "Oracle", ""),
+				ExpectError: regexp.MustCompile(`Invalid\s+Default\s+Cloud\s+Provider`),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_DefaultCloudProviderValidation(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	// config returns an account omitting cloud_provider, with the given extra attributes and product.
+	config := func(defaultCloudProvider string, attributes string, product string) string {
+		return fmt.Sprintf(`
+provider "zesty" {
+  host                   = %q
+  token                  = "test-token"
+  default_cloud_provider = %q
+}
+
+resource "zesty_account" "test" {
+  account = {
+    id          = "123456789012"
+    role_arn    = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    %s
+    products = [{
+      name   = %q
+      active = true
+    }]
+  }
+}
+`, server.URL, defaultCloudProvider, attributes, product)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("Azure", "", "ZestyDisk"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`ZestyDisk\s+is\s+not\s+supported\s+on\s+Azure`),
+			},
+			{
+				Config:      config("AWS", `subscription_id = "00000000-0000-0000-0000-000000000000"`, "Kompass"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`subscription_id\s+can\s+only\s+be\s+set\s+for\s+Azure\s+accounts`),
+			},
+			{
+				Config:      config("AWS", `region = "us-east-11"`, "Kompass"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Region\s+"us-east-11"\s+is\s+not\s+a\s+known\s+AWS\s+region,\s+did\s+you\s+mean\s+"us-east-1"\?`),
+			},
+			{
+				// The checks pass when the default cloud provider supports the configuration.
+				Config:             config("Azure", `region = "westeurope"`, "Kompass"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_UpdateSendsOnlyChanges(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceTagsConfig(server, `{ team = "platform" }`),
+			},
+			{
+				Config: testAccAccountResourceTagsConfig(server, `{ team = "platform", env = "prod" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.tags.env", "prod"),
+					func(_ *terraform.State) error {
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						expected := map[string]any{
+							"accountID":     "123456789012",
+							"cloudProvider": "AWS",
+							"tags":          map[string]any{"env": "prod"},
+						}
+						if !assert.ObjectsAreEqual(expected, api.lastPatch) {
+							return fmt.Errorf("expected patch %v, got %v", expected, api.lastPatch)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountResource_UpdateKeepsUnconfiguredValues(t *testing.T) {
+	api, server := newTestAPI(t)
+	defaults := map[string]any{"replicas": float64(2)}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountResourceTagsConfig(server, `{ team = "platform" }`),
+			},
+			{
+				// The API fills in default values of the product, which the configuration leaves
+				// unset, so changing only the tags must not clear them.
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					account := api.accounts["123456789012"]
+					kompass := account.Products[models.Kompass]
+					kompass.Values = defaults
+					account.Products[models.Kompass] = kompass
+					api.accounts["123456789012"] = account
+				},
+				Config: testAccAccountResourceTagsConfig(server, `{ team = "platform", env = "prod" }`),
+				Check: func(_ *terraform.State) error {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					expected := map[string]any{
+						"accountID":     "123456789012",
+						"cloudProvider": "AWS",
+						"tags":          map[string]any{"env": "prod"},
+					}
+					if !assert.ObjectsAreEqual(expected, api.lastPatch) {
+						return fmt.Errorf("expected patch %v, got %v", expected, api.lastPatch)
+					}
+					if values := api.accounts["123456789012"].Products[models.Kompass].Values; !assert.ObjectsAreEqual(defaults, values) {
+						return fmt.Errorf("expected the values of Kompass to stay %v, got %v", defaults, values)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
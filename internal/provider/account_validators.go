@@ -0,0 +1,561 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+var _ resource.ResourceWithConfigValidators = &AccountResource{}
+
+var productsPath = path.Root("account").AtName("products")
+
+// ConfigValidators returns the validators run against the account configuration.
+func (r *AccountResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		productNamesValidator{},
+		uniqueProductsValidator{},
+		productDependenciesValidator{},
+		productCloudProvidersValidator{},
+		cloudProviderAttributesValidator{},
+		productRegionsValidator{},
+		awsRegionValidator{},
+	}
+}
+
+// configProducts reads the products set from the configuration, along with the path of each
+// product for attribute diagnostics. It reports false when the set, or any product in it, is not
+// yet known and validation should be skipped.
+func configProducts(ctx context.Context, config tfsdk.Config) ([]productModel, []path.Path, bool, diag.Diagnostics) {
+	var set types.Set
+	diags := config.GetAttribute(ctx, productsPath, &set)
+	if diags.HasError() || set.IsNull() || set.IsUnknown() {
+		return nil, nil, false, diags
+	}
+
+	var products []productModel
+	var paths []path.Path
+	for _, element := range set.Elements() {
+		object, ok := element.(types.Object)
+		if !ok || object.IsUnknown() {
+			return nil, nil, false, diags
+		}
+
+		var product productModel
+		diags.Append(object.As(ctx, &product, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return nil, nil, false, diags
+		}
+		if product.Name.IsUnknown() || product.Active.IsUnknown() {
+			return nil, nil, false, diags
+		}
+
+		products = append(products, product)
+		paths = append(paths, productsPath.AtSetValue(element))
+	}
+
+	return products, paths, true, diags
+}
+
+type productNamesValidator struct{}
+
+func (v productNamesValidator) Description(_ context.Context) string {
+	return "Ensures every product name is one of the products known to the provider."
+}
+
+func (v productNamesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v productNamesValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	products, paths, known, diags := configProducts(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if !known {
+		return
+	}
+
+	names := make([]string, len(models.Products))
+	for i, product := range models.Products {
+		names[i] = string(product)
+	}
+
+	for i, product := range products {
+		if name, _ := models.Product(product.Name.ValueString()).Canonical(); name.Valid() {
+			continue
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			paths[i].AtName("name"),
+			"Unknown product",
+			fmt.Sprintf("Product %q is not supported. Valid products are: %s.", product.Name.ValueString(), strings.Join(names, ", ")),
+		)
+	}
+}
+
+type uniqueProductsValidator struct{}
+
+func (v uniqueProductsValidator) Description(_ context.Context) string {
+	return "Ensures every product appears at most once in the products set."
+}
+
+func (v uniqueProductsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v uniqueProductsValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	products, paths, known, diags := configProducts(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if !known {
+		return
+	}
+
+	seen := map[models.Product]bool{}
+	for i, product := range products {
+		name, _ := models.Product(product.Name.ValueString()).Canonical()
+		if !seen[name] {
+			seen[name] = true
+			continue
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			paths[i].AtName("name"),
+			"Duplicate product",
+			fmt.Sprintf("Product %q is configured more than once. Each product may only appear once.", product.Name.ValueString()),
+		)
+	}
+}
+
+type productDependenciesValidator struct{}
+
+func (v productDependenciesValidator) Description(_ context.Context) string {
+	return "Ensures every active product has its prerequisite products active as well."
+}
+
+func (v productDependenciesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v productDependenciesValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	products, paths, known, diags := configProducts(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if !known {
+		return
+	}
+
+	details := map[models.Product]models.ProductDetails{}
+	for _, product := range products {
+		name, _ := models.Product(product.Name.ValueString()).Canonical()
+		details[name] = models.ProductDetails{
+			Active: product.Active.ValueBool(),
+		}
+	}
+
+	for i, product := range products {
+		if !product.Active.ValueBool() {
+			continue
+		}
+
+		name, _ := models.Product(product.Name.ValueString()).Canonical()
+		missing := name.MissingDependencies(details)
+		if len(missing) == 0 {
+			continue
+		}
+
+		names := make([]string, len(missing))
+		for j, dependency := range missing {
+			names[j] = string(dependency)
+		}
+
+		resp.Diagnostics.AddAttributeError(
+			paths[i].AtName("active"),
+			"Missing product dependency",
+			fmt.Sprintf("Product %q requires %s to be active on the account.", product.Name.ValueString(), strings.Join(names, ", ")),
+		)
+	}
+}
+
+// productCloudProvidersValidator rejects products that are not available on the account's cloud
+// provider, according to models.ProductCloudProviders.
+type productCloudProvidersValidator struct{}
+
+// cloudProviderConfigValidator is implemented by the config validators that depend on the cloud
+// provider of the account. They skip accounts that omit cloud_provider, as validators run before the
+// provider, and its default_cloud_provider, is configured, so ModifyPlan runs them again with the
+// default cloud provider.
+type cloudProviderConfigValidator interface {
+	validateCloudProvider(ctx context.Context, config tfsdk.Config, cloudProvider models.CloudProvider, diags *diag.Diagnostics)
+}
+
+// cloudProviderConfigValidators lists the config validators ModifyPlan runs for accounts using the
+// default cloud provider.
+var cloudProviderConfigValidators = []cloudProviderConfigValidator{
+	productCloudProvidersValidator{},
+	cloudProviderAttributesValidator{},
+	awsRegionValidator{},
+}
+
+// configCloudProvider reads the cloud provider of the account from the configuration. It reports
+// false when cloud_provider is null, unknown or invalid, and validation should be skipped; invalid
+// values are reported by cloudProviderValidator on the attribute itself.
+func configCloudProvider(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) (models.CloudProvider, bool) {
+	var cloudProvider types.String
+	diags.Append(config.GetAttribute(ctx, path.Root("account").AtName("cloud_provider"), &cloudProvider)...)
+	if diags.HasError() || cloudProvider.IsNull() || cloudProvider.IsUnknown() {
+		return "", false
+	}
+	parsed, err := models.ParseCloudProvider(cloudProvider.ValueString())
+	if err != nil {
+		return "", false
+	}
+	return parsed, true
+}
+
+func (v productCloudProvidersValidator) Description(_ context.Context) string {
+	return "Ensures every product is available on the account's cloud provider."
+}
+
+func (v productCloudProvidersValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v productCloudProvidersValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	if cloudProvider, ok := configCloudProvider(ctx, req.Config, &resp.Diagnostics); ok {
+		v.validateCloudProvider(ctx, req.Config, cloudProvider, &resp.Diagnostics)
+	}
+}
+
+func (v productCloudProvidersValidator) validateCloudProvider(ctx context.Context, config tfsdk.Config, parsed models.CloudProvider, diags *diag.Diagnostics) {
+	products, paths, known, productsDiags := configProducts(ctx, config)
+	diags.Append(productsDiags...)
+	if !known {
+		return
+	}
+
+	for i, product := range products {
+		name, _ := models.Product(product.Name.ValueString()).Canonical()
+		if name.SupportedOn(parsed) {
+			continue
+		}
+
+		supported := make([]string, len(models.ProductCloudProviders[name]))
+		for j, c := range models.ProductCloudProviders[name] {
+			supported[j] = string(c)
+		}
+
+		diags.AddAttributeError(
+			paths[i].AtName("name"),
+			"Product not supported on cloud provider",
+			fmt.Sprintf("%s is not supported on %s. It is available on: %s.", name, parsed, strings.Join(supported, ", ")),
+		)
+	}
+}
+
+// cloudProviderValidator ensures a cloud_provider attribute names one of models.CloudProviders.
+type cloudProviderValidator struct{}
+
+var _ validator.String = cloudProviderValidator{}
+
+func (v cloudProviderValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("Value must be one of: %s.", strings.Join(cloudProviderNames(), ", "))
+}
+
+func (v cloudProviderValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v cloudProviderValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := models.ParseCloudProvider(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Unknown cloud provider",
+			fmt.Sprintf("Invalid cloud provider: %s.", err),
+		)
+	}
+}
+
+func cloudProviderNames() []string {
+	names := make([]string, len(models.CloudProviders))
+	for i, cloudProvider := range models.CloudProviders {
+		names[i] = string(cloudProvider)
+	}
+	return names
+}
+
+// oneOfValidator ensures a string attribute is one of values.
+type oneOfValidator struct {
+	values []string
+}
+
+var _ validator.String = oneOfValidator{}
+
+func (v oneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("Value must be one of: %s.", strings.Join(v.values, ", "))
+}
+
+func (v oneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v oneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for _, value := range v.values {
+		if req.ConfigValue.ValueString() == value {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid value",
+		fmt.Sprintf("Value %q is not supported. %s", req.ConfigValue.ValueString(), v.Description(ctx)),
+	)
+}
+
+// cloudProviderAttributes maps account attributes that only apply to one cloud provider to that
+// cloud provider.
+var cloudProviderAttributes = map[string]models.CloudProvider{
+	"subscription_id": models.Azure,
+	"project_id":      models.GCP,
+}
+
+// cloudProviderAttributesValidator rejects cloud-provider specific attributes set on accounts of
+// another cloud provider.
+type cloudProviderAttributesValidator struct{}
+
+func (v cloudProviderAttributesValidator) Description(_ context.Context) string {
+	return "Ensures cloud-provider specific attributes are only set for accounts of that cloud provider."
+}
+
+func (v cloudProviderAttributesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v cloudProviderAttributesValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	if cloudProvider, ok := configCloudProvider(ctx, req.Config, &resp.Diagnostics); ok {
+		v.validateCloudProvider(ctx, req.Config, cloudProvider, &resp.Diagnostics)
+	}
+}
+
+func (v cloudProviderAttributesValidator) validateCloudProvider(ctx context.Context, config tfsdk.Config, parsed models.CloudProvider, diags *diag.Diagnostics) {
+	for name, required := range cloudProviderAttributes {
+		attributePath := path.Root("account").AtName(name)
+
+		var value types.String
+		diags.Append(config.GetAttribute(ctx, attributePath, &value)...)
+		if diags.HasError() {
+			return
+		}
+		if value.IsNull() || parsed == required {
+			continue
+		}
+
+		diags.AddAttributeError(
+			attributePath,
+			"Attribute not supported for cloud provider",
+			fmt.Sprintf("%s can only be set for %s accounts, got cloud provider %q.", name, required, parsed),
+		)
+	}
+}
+
+// guidPattern matches GUIDs such as Azure subscription IDs.
+var guidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// gcpProjectIDPattern matches GCP project IDs.
+var gcpProjectIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+
+// patternValidator ensures a string attribute matches pattern. description completes the sentence
+// "Value must be ...".
+type patternValidator struct {
+	pattern     *regexp.Regexp
+	description string
+}
+
+var _ validator.String = patternValidator{}
+
+func (v patternValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("Value must be %s.", v.description)
+}
+
+func (v patternValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v patternValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if v.pattern.MatchString(req.ConfigValue.ValueString()) {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid format",
+		fmt.Sprintf("Value %q is not valid. %s", req.ConfigValue.ValueString(), v.Description(ctx)),
+	)
+}
+
+// rfc3339Validator ensures a string attribute is a time in RFC3339 format.
+type rfc3339Validator struct{}
+
+var _ validator.String = rfc3339Validator{}
+
+func (v rfc3339Validator) Description(_ context.Context) string {
+	return "Value must be a time in RFC3339 format, such as 2024-05-01T12:00:00Z."
+}
+
+func (v rfc3339Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err == nil {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid time",
+		fmt.Sprintf("Value %q is not valid. %s", req.ConfigValue.ValueString(), v.Description(ctx)),
+	)
+}
+
+// productRegionsValidator warns about products setting the account region as their own region,
+// which is redundant as products run in the account region unless they override it. The precedence
+// of the two regions is decided by productRegionOverride.
+type productRegionsValidator struct{}
+
+func (v productRegionsValidator) Description(_ context.Context) string {
+	return "Warns about products setting a region that is already the account region."
+}
+
+func (v productRegionsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v productRegionsValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var accountRegion types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("account").AtName("region"), &accountRegion)...)
+	if resp.Diagnostics.HasError() || accountRegion.IsUnknown() {
+		return
+	}
+	if accountRegion.IsNull() {
+		accountRegion = types.StringValue(defaultAccountRegion)
+	}
+
+	products, paths, known, diags := configProducts(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if !known {
+		return
+	}
+
+	for i, product := range products {
+		if product.Region.IsNull() || product.Region.IsUnknown() || productRegionOverride(product.Region, accountRegion) != nil {
+			continue
+		}
+
+		resp.Diagnostics.AddAttributeWarning(
+			paths[i].AtName("region"),
+			"Redundant product region",
+			fmt.Sprintf("Product %q sets region %s, which is already the account region. Products run in the account region unless they set another region, so remove region from the product, or set it to the region the product should run in instead.", product.Name.ValueString(), product.Region.ValueString()),
+		)
+	}
+}
+
+// awsRegionValidator rejects AWS accounts in a region missing from models.AWSRegions, suggesting the
+// closest known region, so that typos are caught at plan time rather than by AWS during apply.
+// Accounts of other cloud providers are not checked.
+type awsRegionValidator struct{}
+
+func (v awsRegionValidator) Description(_ context.Context) string {
+	return "Ensures the region of AWS accounts is a known AWS region."
+}
+
+func (v awsRegionValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v awsRegionValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	if cloudProvider, ok := configCloudProvider(ctx, req.Config, &resp.Diagnostics); ok {
+		v.validateCloudProvider(ctx, req.Config, cloudProvider, &resp.Diagnostics)
+	}
+}
+
+func (v awsRegionValidator) validateCloudProvider(ctx context.Context, config tfsdk.Config, cloudProvider models.CloudProvider, diags *diag.Diagnostics) {
+	if cloudProvider != models.AWS {
+		return
+	}
+
+	var region types.String
+	diags.Append(config.GetAttribute(ctx, path.Root("account").AtName("region"), &region)...)
+	if diags.HasError() || region.IsNull() || region.IsUnknown() {
+		return
+	}
+	if slices.Contains(models.AWSRegions, region.ValueString()) {
+		return
+	}
+
+	diags.AddAttributeError(
+		path.Root("account").AtName("region"),
+		"Unknown AWS region",
+		fmt.Sprintf("Region %q is not a known AWS region, did you mean %q? If AWS opened the region recently, please report this issue to Zesty Support.", region.ValueString(), closestMatch(region.ValueString(), models.AWSRegions)),
+	)
+}
+
+// closestMatch returns the candidate with the smallest edit distance to s, the first one on ties.
+func closestMatch(s string, candidates []string) string {
+	var closest string
+	best := -1
+	for _, candidate := range candidates {
+		if distance := editDistance(strings.ToLower(s), candidate); best < 0 || distance < best {
+			closest, best = candidate, distance
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b: the number of single-byte
+// insertions, deletions and substitutions turning a into b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
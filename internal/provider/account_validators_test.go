@@ -0,0 +1,210 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+	"github.com/zesty-co/terraform-provider-zesty/internal/provider"
+)
+
+// validateAccountConfig runs the config validators of the account resource against an account with
+// the given attributes and products, leaving every other attribute null.
+func validateAccountConfig(t *testing.T, account map[string]tftypes.Value, products ...map[string]tftypes.Value) diag.Diagnostics {
+	ctx := context.Background()
+	r := provider.NewAccountResource()
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	rootType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	accountType := rootType.AttributeTypes["account"].(tftypes.Object)
+	productsType := accountType.AttributeTypes["products"].(tftypes.Set)
+	productType := productsType.ElementType.(tftypes.Object)
+
+	productValues := make([]tftypes.Value, len(products))
+	for i, product := range products {
+		productValues[i] = objectValue(productType, product)
+	}
+	accountAttributes := map[string]tftypes.Value{"products": tftypes.NewValue(productsType, productValues)}
+	for name, value := range account {
+		accountAttributes[name] = value
+	}
+
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    objectValue(rootType, map[string]tftypes.Value{"account": objectValue(accountType, accountAttributes)}),
+	}
+
+	var diags diag.Diagnostics
+	for _, v := range r.(resource.ResourceWithConfigValidators).ConfigValidators(ctx) {
+		resp := &resource.ValidateConfigResponse{}
+		v.ValidateResource(ctx, resource.ValidateConfigRequest{Config: config}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
+	return diags
+}
+
+// objectValue returns an object of type typ with the given attributes, and null for the others.
+func objectValue(typ tftypes.Object, attributes map[string]tftypes.Value) tftypes.Value {
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range typ.AttributeTypes {
+		if value, ok := attributes[name]; ok {
+			values[name] = value
+			continue
+		}
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	return tftypes.NewValue(typ, values)
+}
+
+func TestAccountResource_ProductRegionsValidator(t *testing.T) {
+	product := func(region any) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"name":   tftypes.NewValue(tftypes.String, "Kompass"),
+			"active": tftypes.NewValue(tftypes.Bool, true),
+			"region": tftypes.NewValue(tftypes.String, region),
+		}
+	}
+	account := func(region any) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"id":             tftypes.NewValue(tftypes.String, "123456789012"),
+			"cloud_provider": tftypes.NewValue(tftypes.String, "AWS"),
+			"region":         tftypes.NewValue(tftypes.String, region),
+		}
+	}
+
+	tests := []struct {
+		name          string
+		accountRegion any
+		productRegion any
+		warning       bool
+	}{
+		{name: "no product region", accountRegion: "eu-west-1", productRegion: nil},
+		{name: "override", accountRegion: "eu-west-1", productRegion: "us-west-2"},
+		{name: "override of the default region", accountRegion: nil, productRegion: "eu-west-1"},
+		{name: "unknown product region", accountRegion: "eu-west-1", productRegion: tftypes.UnknownValue},
+		{name: "unknown account region", accountRegion: tftypes.UnknownValue, productRegion: "eu-west-1"},
+		{name: "account region", accountRegion: "eu-west-1", productRegion: "eu-west-1", warning: true},
+		{name: "default region", accountRegion: nil, productRegion: "us-east-1", warning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateAccountConfig(t, account(tt.accountRegion), product(tt.productRegion))
+			require.False(t, diags.HasError(), diags)
+
+			if !tt.warning {
+				assert.Empty(t, diags.Warnings())
+				return
+			}
+			require.Len(t, diags.Warnings(), 1)
+			assert.Equal(t, "Redundant product region", diags.Warnings()[0].Summary())
+			assert.Contains(t, diags.Warnings()[0].Detail(), `Product "Kompass" sets region`)
+		})
+	}
+}
+
+func TestAccountResource_AWSRegionValidator(t *testing.T) {
+	account := func(cloudProvider, region any) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"id":             tftypes.NewValue(tftypes.String, "123456789012"),
+			"cloud_provider": tftypes.NewValue(tftypes.String, cloudProvider),
+			"region":         tftypes.NewValue(tftypes.String, region),
+		}
+	}
+	product := map[string]tftypes.Value{
+		"name":   tftypes.NewValue(tftypes.String, "Kompass"),
+		"active": tftypes.NewValue(tftypes.Bool, true),
+	}
+
+	tests := []struct {
+		name          string
+		cloudProvider any
+		region        any
+		suggestion    string
+	}{
+		{name: "valid", cloudProvider: "AWS", region: "eu-west-1"},
+		{name: "GovCloud", cloudProvider: "AWS", region: "us-gov-west-1"},
+		{name: "default region", cloudProvider: "AWS", region: nil},
+		{name: "unknown region", cloudProvider: "AWS", region: tftypes.UnknownValue},
+		{name: "typo", cloudProvider: "AWS", region: "us-east-11", suggestion: "us-east-1"},
+		{name: "transposed", cloudProvider: "AWS", region: "eu-wset-2", suggestion: "eu-west-2"},
+		{name: "upper case", cloudProvider: "AWS", region: "AP-SOUTHEAST-2", suggestion: "ap-southeast-2"},
+		{name: "Azure", cloudProvider: "Azure", region: "westeurope"},
+		{name: "GCP", cloudProvider: "GCP", region: "us-east11"},
+		{name: "unknown cloud provider", cloudProvider: tftypes.UnknownValue, region: "us-east-11"},
+		{name: "default cloud provider", cloudProvider: nil, region: "us-east-11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateAccountConfig(t, account(tt.cloudProvider, tt.region), product)
+
+			if tt.suggestion == "" {
+				assert.False(t, diags.HasError(), diags)
+				return
+			}
+			require.Len(t, diags.Errors(), 1)
+			assert.Equal(t, "Unknown AWS region", diags.Errors()[0].Summary())
+			assert.Contains(t, diags.Errors()[0].Detail(), fmt.Sprintf("Region %q is not a known AWS region, did you mean %q?", tt.region, tt.suggestion))
+		})
+	}
+}
+
+func TestAccountResource_ProductDependenciesValidator(t *testing.T) {
+	original := models.ProductDependencies
+	models.ProductDependencies = map[models.Product][]models.Product{
+		models.ZestyDisk: {models.Kompass},
+	}
+	t.Cleanup(func() { models.ProductDependencies = original })
+
+	account := map[string]tftypes.Value{
+		"id":             tftypes.NewValue(tftypes.String, "123456789012"),
+		"cloud_provider": tftypes.NewValue(tftypes.String, "AWS"),
+	}
+	product := func(name string, active bool) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"name":   tftypes.NewValue(tftypes.String, name),
+			"active": tftypes.NewValue(tftypes.Bool, active),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		products []map[string]tftypes.Value
+		missing  bool
+	}{
+		{name: "satisfied", products: []map[string]tftypes.Value{product("ZestyDisk", true), product("Kompass", true)}},
+		{name: "inactive dependent", products: []map[string]tftypes.Value{product("ZestyDisk", false)}},
+		{name: "no dependencies", products: []map[string]tftypes.Value{product("Kompass", true)}},
+		{name: "missing", products: []map[string]tftypes.Value{product("ZestyDisk", true)}, missing: true},
+		{name: "inactive dependency", products: []map[string]tftypes.Value{product("ZestyDisk", true), product("Kompass", false)}, missing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateAccountConfig(t, account, tt.products...)
+
+			if !tt.missing {
+				assert.False(t, diags.HasError(), diags)
+				return
+			}
+			require.Len(t, diags.Errors(), 1)
+			assert.Equal(t, "Missing product dependency", diags.Errors()[0].Summary())
+			assert.Equal(t, `Product "ZestyDisk" requires Kompass to be active on the account.`, diags.Errors()[0].Detail())
+			withPath, ok := diags.Errors()[0].(diag.DiagnosticWithPath)
+			require.True(t, ok)
+			assert.True(t, strings.HasSuffix(withPath.Path().String(), ".active"), withPath.Path())
+		})
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/zesty-co/terraform-provider-zesty/internal/client"
@@ -14,7 +18,11 @@ import (
 )
 
 type AccountsDataSource struct {
-	client *client.Client
+	client            *client.Client
+	valuesFormat      string
+	omitProductValues bool
+	// readyStatuses are the onboarding statuses that set the ready attribute of accounts.
+	readyStatuses []models.OnboardingStatus
 }
 
 var (
@@ -31,25 +39,55 @@ func (d *AccountsDataSource) Metadata(_ context.Context, req datasource.Metadata
 }
 
 type accountsDataSourceModel struct {
-	Accounts []accountModel `tfsdk:"accounts"`
+	CloudProvider    types.String             `tfsdk:"cloud_provider"`
+	Product          types.String             `tfsdk:"product"`
+	OrganizationID   types.Int64              `tfsdk:"organization_id"`
+	OnboardingStatus types.String             `tfsdk:"onboarding_status"`
+	UpdatedAfter     types.String             `tfsdk:"updated_after"`
+	ExternalID       types.String             `tfsdk:"external_id"`
+	Strict           types.Bool               `tfsdk:"strict"`
+	SortBy           types.String             `tfsdk:"sort_by"`
+	SortOrder        types.String             `tfsdk:"sort_order"`
+	AccountCount     types.Int64              `tfsdk:"account_count"`
+	Accounts         []accountDataSourceModel `tfsdk:"accounts"`
+}
+
+// accountDataSourceModel is an account as listed by the data source, which also exposes its additional
+// data without sensitive keys.
+type accountDataSourceModel struct {
+	accountModel
+	AdditionalDataJSON types.String `tfsdk:"additional_data_json"`
 }
 
 type accountModel struct {
-	ID               types.String   `tfsdk:"id"`
-	CloudProvider    types.String   `tfsdk:"cloud_provider"`
-	Region           types.String   `tfsdk:"region"`
-	RoleARN          types.String   `tfsdk:"role_arn"`
-	ExternalID       types.String   `tfsdk:"external_id"`
-	StorageClassName types.String   `tfsdk:"storage_class_name"`
-	Products         []productModel `tfsdk:"products"`
-	Cur              *curModel      `tfsdk:"cur"`
-	Athena           *athenaModel   `tfsdk:"athena"`
+	ID               types.String            `tfsdk:"id"`
+	OrganizationID   types.Int64             `tfsdk:"organization_id"`
+	CloudProvider    types.String            `tfsdk:"cloud_provider"`
+	Region           types.String            `tfsdk:"region"`
+	RoleARN          types.String            `tfsdk:"role_arn"`
+	ExternalID       types.String            `tfsdk:"external_id"`
+	StorageClassName types.String            `tfsdk:"storage_class_name"`
+	SubscriptionID   types.String            `tfsdk:"subscription_id"`
+	ProjectID        types.String            `tfsdk:"project_id"`
+	Tags             map[string]types.String `tfsdk:"tags"`
+	Products         []productModel          `tfsdk:"products"`
+	Cur              *curModel               `tfsdk:"cur"`
+	Athena           *athenaModel            `tfsdk:"athena"`
+	ScanCoverage     types.Float64           `tfsdk:"scan_coverage"`
+	CreatedAt        types.String            `tfsdk:"created_at"`
+	UpdatedAt        types.String            `tfsdk:"updated_at"`
+	AdditionalData   types.String            `tfsdk:"additional_data"`
+	OnboardingStatus types.String            `tfsdk:"onboarding_status"`
+	Ready            types.Bool              `tfsdk:"ready"`
 }
 
 type productModel struct {
-	Name   types.String `tfsdk:"name"`
-	Active types.Bool   `tfsdk:"active"`
-	Values types.String `tfsdk:"values"`
+	Name       types.String `tfsdk:"name"`
+	Active     types.Bool   `tfsdk:"active"`
+	Values     types.String `tfsdk:"values"`
+	ValuesMap  types.Map    `tfsdk:"values_map"`
+	ValuesHash types.String `tfsdk:"values_hash"`
+	Region     types.String `tfsdk:"region"`
 }
 
 type curModel struct {
@@ -73,6 +111,59 @@ func (d *AccountsDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 	resp.Schema = schema.Schema{
 		Description: "Fetches the list of accounts.",
 		Attributes: map[string]schema.Attribute{
+			"cloud_provider": schema.StringAttribute{
+				Description: "Only return accounts on this cloud provider (one of AWS, Azure, GCP or OCI). Combined with other filters using AND.",
+				Optional:    true,
+				Validators: []validator.String{
+					cloudProviderValidator{},
+				},
+			},
+			"product": schema.StringAttribute{
+				Description: "Only return accounts with this product (e.g. Kompass). Combined with other filters using AND.",
+				Optional:    true,
+			},
+			"organization_id": schema.Int64Attribute{
+				Description: "Only return accounts in this Zesty organization. Combined with other filters using AND.",
+				Optional:    true,
+			},
+			"onboarding_status": schema.StringAttribute{
+				Description: "Only return accounts with this onboarding status. Combined with other filters using AND.",
+				Optional:    true,
+			},
+			"updated_after": schema.StringAttribute{
+				Description: "Only return accounts updated after this time (RFC3339), for incremental syncs. Combined with other filters using AND.",
+				Optional:    true,
+				Validators: []validator.String{
+					rfc3339Validator{},
+				},
+			},
+			"external_id": schema.StringAttribute{
+				Description: "Only return the account with this external ID, for automation that does not know the account ID. The account is looked up directly, so no other filter may be set. Returns no account when none has the external ID.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"sort_by": schema.StringAttribute{
+				Description: "Attribute the accounts are ordered by, one of id, cloud_provider or onboarding_status. Ties are broken by id. Defaults to id.",
+				Optional:    true,
+				Validators: []validator.String{
+					oneOfValidator{values: []string{sortByID, sortByCloudProvider, sortByOnboardingStatus}},
+				},
+			},
+			"sort_order": schema.StringAttribute{
+				Description: "Order of the accounts, either asc or desc. Defaults to asc.",
+				Optional:    true,
+				Validators: []validator.String{
+					oneOfValidator{values: []string{sortOrderAsc, sortOrderDesc}},
+				},
+			},
+			"strict": schema.BoolAttribute{
+				Description: "Fail the read when an account returned by the API is malformed. By default malformed accounts are skipped with a warning.",
+				Optional:    true,
+			},
+			"account_count": schema.Int64Attribute{
+				Description: "Number of accounts returned, after filtering and skipping malformed accounts",
+				Computed:    true,
+			},
 			"accounts": schema.ListNestedAttribute{
 				Description: "List of accounts.",
 				Computed:    true,
@@ -82,24 +173,74 @@ func (d *AccountsDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 							Description: "Account ID",
 							Computed:    true,
 						},
+						"organization_id": schema.Int64Attribute{
+							Description: "ID of the Zesty organization the account belongs to",
+							Computed:    true,
+						},
 						"cloud_provider": schema.StringAttribute{
-							Description: "Name of cloud provider (e.g. AWS, GCP, Azure)",
+							Description: "Name of cloud provider (e.g. AWS, Azure, GCP, OCI)",
 							Computed:    true,
 						},
 						"role_arn": schema.StringAttribute{
-							Description: "Role ARN generated on the cloud provider",
+							Description: "Role ARN generated on the cloud provider, or the OCID of the dynamic group for OCI",
 							Computed:    true,
 						},
 						"external_id": schema.StringAttribute{
 							Description: "External ID (UUID)",
 							Computed:    true,
+							Sensitive:   true,
 						},
 						"region": schema.StringAttribute{
-							Optional: true,
-							Computed: false,
+							Description: "Region of the account, such as the AWS region it was onboarded in. Null when the API does not report one",
+							Computed:    true,
+						},
+						"tags": schema.MapAttribute{
+							Description: "Tags attached to the account, such as team or cost center",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"storage_class_name": schema.StringAttribute{
+							Description: "Storage class name of the cluster",
+							Computed:    true,
+						},
+						"subscription_id": schema.StringAttribute{
+							Description: "Azure subscription ID of the account. Null for other cloud providers",
+							Computed:    true,
 						},
-						"products": schema.ListNestedAttribute{
-							Description: "List of products activated on the account",
+						"project_id": schema.StringAttribute{
+							Description: "GCP project ID of the account. Null for other cloud providers",
+							Computed:    true,
+						},
+						"scan_coverage": schema.Float64Attribute{
+							Description: "Percentage of the account's resources visible to Zesty. Null when the account has not been scanned yet.",
+							Computed:    true,
+						},
+						"created_at": schema.StringAttribute{
+							Description: "Time the account was created (RFC3339)",
+							Computed:    true,
+						},
+						"updated_at": schema.StringAttribute{
+							Description: "Time the account was last updated (RFC3339)",
+							Computed:    true,
+						},
+						"additional_data": schema.StringAttribute{
+							Description: "JSON encoded additional data stored by Zesty for the account, without the roleARN and externalID exposed as role_arn and external_id, and without sensitive keys such as token, secret, password and apiKey at any depth",
+							Computed:    true,
+						},
+						"additional_data_json": schema.StringAttribute{
+							Description: "JSON encoded additional data stored by Zesty for the account, including fields the provider does not model, for use with jsondecode. Sensitive keys such as externalID, token, secret, password and apiKey are removed at any depth",
+							Computed:    true,
+						},
+						"onboarding_status": schema.StringAttribute{
+							Description: "Onboarding status of the account as reported by Zesty",
+							Computed:    true,
+						},
+						"ready": schema.BoolAttribute{
+							Description: "Whether the account is fully onboarded, i.e. its onboarding status is one of the provider's ready_onboarding_statuses",
+							Computed:    true,
+						},
+						"products": schema.SetNestedAttribute{
+							Description: "Set of products activated on the account",
 							Computed:    true,
 							NestedObject: schema.NestedAttributeObject{
 								Attributes: map[string]schema.Attribute{
@@ -112,7 +253,20 @@ func (d *AccountsDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 										Computed:    true,
 									},
 									"values": schema.StringAttribute{
-										Description: "Key-value pairs of product-specific values",
+										Description: "Key-value pairs of product-specific values, encoded in the provider's values_format",
+										Computed:    true,
+									},
+									"values_map": schema.MapAttribute{
+										Description: "Product-specific values by key, for use in expressions such as values_map[\"threshold\"]. Strings are kept as they are, and other values, including nested objects and lists, are encoded as JSON",
+										ElementType: types.StringType,
+										Computed:    true,
+									},
+									"values_hash": schema.StringAttribute{
+										Description: "SHA-256 checksum of the values, which only changes with their content, not with their encoding. Kept when the provider sets omit_product_values",
+										Computed:    true,
+									},
+									"region": schema.StringAttribute{
+										Description: "Region the product runs in, falling back to the account region",
 										Computed:    true,
 									},
 								},
@@ -179,78 +333,100 @@ func (d *AccountsDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 
 func (d *AccountsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state accountsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	accounts, err := d.client.GetAccounts()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Read Zesty Onboarded Accounts",
-			err.Error(),
+	if !state.ExternalID.IsNull() && (!state.CloudProvider.IsNull() || !state.Product.IsNull() || !state.OrganizationID.IsNull() || !state.OnboardingStatus.IsNull() || !state.UpdatedAfter.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("external_id"),
+			"Conflicting filters",
+			"external_id looks up a single account and cannot be combined with cloud_provider, product, organization_id, onboarding_status or updated_after.",
 		)
 		return
 	}
 
-	tflog.Info(ctx, "Received accounts", map[string]any{"count": len(*accounts)})
-
-	for _, account := range *accounts {
-		roleARN, exists := account.AdditionalData["roleARN"]
-		if !exists {
-			resp.Diagnostics.AddError(
-				"Missing role ARN for account",
-				account.AccountID,
-			)
-			return
-		}
-		roleARNString, ok := roleARN.(string)
-		if !ok {
-			resp.Diagnostics.AddError(
-				"Erroneous role ARN for account",
-				account.AccountID,
+	var cloudProvider models.CloudProvider
+	if !state.CloudProvider.IsNull() {
+		parsed, err := models.ParseCloudProvider(state.CloudProvider.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("cloud_provider"),
+				"Invalid cloud provider",
+				fmt.Sprintf("Could not parse cloud provider: %s", err),
 			)
 			return
 		}
+		cloudProvider = parsed
+	}
 
-		externalID, exists := account.AdditionalData["externalID"]
-		if !exists {
-			resp.Diagnostics.AddError(
-				"Missing external ID for account",
-				account.AccountID,
+	filter := client.AccountsFilter{
+		CloudProvider:    cloudProvider,
+		Product:          models.Product(state.Product.ValueString()),
+		OrganizationID:   state.OrganizationID.ValueInt64(),
+		OnboardingStatus: models.OnboardingStatus(state.OnboardingStatus.ValueString()),
+	}
+	if !state.UpdatedAfter.IsNull() {
+		updatedAfter, err := time.Parse(time.RFC3339, state.UpdatedAfter.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("updated_after"),
+				"Invalid time",
+				fmt.Sprintf("Could not parse updated_after as RFC3339: %s", err),
 			)
 			return
 		}
-		externalIDString, ok := externalID.(string)
-		if !ok {
-			resp.Diagnostics.AddError(
-				"Erroneous external ID for account",
-				account.AccountID,
+		filter.UpdatedAfter = updatedAfter
+	}
+
+	var accounts *[]models.Account
+	var err error
+	if !state.ExternalID.IsNull() {
+		accounts, err = d.accountByExternalID(ctx, state.ExternalID.ValueString())
+	} else {
+		accounts, err = d.client.GetAccounts(ctx, filter)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Read Zesty Onboarded Accounts",
+			err.Error(),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "Received accounts", map[string]any{"count": len(*accounts)})
+
+	for _, account := range *accounts {
+		accountState, warnings, err := toAccountState(&account, d.valuesFormat, d.readyStatuses)
+		resp.Diagnostics.Append(withAccountLabel(warnings, accountLabel(types.StringValue(account.AccountID), types.StringValue(string(account.CloudProvider))))...)
+		if err != nil {
+			if state.Strict.ValueBool() {
+				resp.Diagnostics.AddError(
+					"Malformed account",
+					fmt.Sprintf("Account %s: %s", account.AccountID, err),
+				)
+				return
+			}
+			resp.Diagnostics.AddWarning(
+				"Skipping malformed account",
+				fmt.Sprintf("Account %s: %s. Set strict = true to fail instead.", account.AccountID, err),
 			)
-			return
-		}
-		accountState := accountModel{
-			ID:            types.StringValue(account.AccountID),
-			CloudProvider: types.StringValue(string(account.CloudProvider)),
-			RoleARN:       types.StringValue(roleARNString),
-			ExternalID:    types.StringValue(externalIDString),
+			continue
 		}
 
-		var productNames []string
-		for name := range account.Products {
-			productNames = append(productNames, string(name))
-		}
-		sort.Strings(productNames)
-
-		for _, name := range productNames {
-			details := account.Products[models.Product(name)]
-			accountState.Products = append(accountState.Products, productModel{
-				Name:   types.StringValue(name),
-				Active: types.BoolValue(details.Active),
-			})
+		if d.omitProductValues {
+			omitProductValues(accountState.Products)
 		}
 
-		tflog.Info(ctx, "Adding account to state", map[string]any{"account": accountState})
+		tflog.Info(ctx, "Adding account to state", map[string]any{"account": loggableAccount(accountState.accountModel)})
 
 		state.Accounts = append(state.Accounts, accountState)
 	}
 
+	sortAccounts(state.Accounts, state.SortBy.ValueString(), state.SortOrder.ValueString())
+	state.AccountCount = types.Int64Value(int64(len(state.Accounts)))
+
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -258,20 +434,91 @@ func (d *AccountsDataSource) Read(ctx context.Context, req datasource.ReadReques
 	}
 }
 
+// accountByExternalID returns the account with externalID as a list, which is empty when no account
+// has it.
+func (d *AccountsDataSource) accountByExternalID(ctx context.Context, externalID string) (*[]models.Account, error) {
+	account, err := d.client.GetAccountByExternalID(ctx, externalID)
+	if client.IsNotFound(err) {
+		return &[]models.Account{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &[]models.Account{*account}, nil
+}
+
+// Keys and orders accepted by the sort_by and sort_order attributes.
+const (
+	sortByID               = "id"
+	sortByCloudProvider    = "cloud_provider"
+	sortByOnboardingStatus = "onboarding_status"
+
+	sortOrderAsc  = "asc"
+	sortOrderDesc = "desc"
+)
+
+// sortAccounts orders accounts by sortBy, breaking ties by ID, so the list is stable regardless of
+// the order the API returns. Empty sortBy and sortOrder sort by ID in ascending order.
+func sortAccounts(accounts []accountDataSourceModel, sortBy string, sortOrder string) {
+	key := func(account accountDataSourceModel) string {
+		switch sortBy {
+		case sortByCloudProvider:
+			return account.CloudProvider.ValueString()
+		case sortByOnboardingStatus:
+			return account.OnboardingStatus.ValueString()
+		default:
+			return account.ID.ValueString()
+		}
+	}
+
+	sort.SliceStable(accounts, func(i, j int) bool {
+		a, b := accounts[i], accounts[j]
+		if sortOrder == sortOrderDesc {
+			a, b = b, a
+		}
+		if ka, kb := key(a), key(b); ka != kb {
+			return ka < kb
+		}
+		return a.ID.ValueString() < b.ID.ValueString()
+	})
+}
+
+// toAccountState converts an account returned by the API into its data source model: the model
+// ToModel converts it into, with the fields only the data source has. It returns an error when the
+// account is missing fields the model requires. Values that cannot be encoded are returned as
+// warnings, and left empty.
+func toAccountState(account *models.Account, valuesFormat string, readyStatuses []models.OnboardingStatus) (accountDataSourceModel, diag.Diagnostics, error) {
+	model, diags := ToModel(account, valuesFormat, readyStatuses)
+	if diags.HasError() {
+		first := diags.Errors()[0]
+		return accountDataSourceModel{}, nil, fmt.Errorf("%s: %s", first.Summary(), first.Detail())
+	}
+
+	additionalDataJSON, err := additionalDataValue(withoutSensitiveKeys(account.AdditionalData))
+	if err != nil {
+		return accountDataSourceModel{}, nil, fmt.Errorf("erroneous additional data: %w", err)
+	}
+
+	return accountDataSourceModel{accountModel: *model, AdditionalDataJSON: additionalDataJSON}, diags, nil
+}
+
 func (d *AccountsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.Client)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected: *client.Client, got: %T.\nPlease report this issue to Zesty Support.", req.ProviderData),
+			fmt.Sprintf("Expected: *providerData, got: %T.\nPlease report this issue to Zesty Support.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.client = data.client
+	d.valuesFormat = data.valuesFormat
+	d.omitProductValues = data.omitProductValues
+	d.readyStatuses = data.readyStatuses
 }
@@ -0,0 +1,563 @@
+package provider_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+func TestAccAccountsDataSource_ValuesFormatJSON(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.accounts["123456789012"] = models.Account{
+		AccountID:        "123456789012",
+		CloudProvider:    models.AWS,
+		StorageClassName: "ebs-sc",
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {
+				Active: true,
+				Values: map[string]any{"threshold": float64(80), "regions": []any{"us-east-1"}},
+			},
+		},
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/ZestyIamRole",
+			"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+		},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host          = %q
+  token         = "test-token"
+  values_format = "json"
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.storage_class_name", "ebs-sc"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.zesty_accounts.all", "accounts.0.products.*", map[string]string{
+						"values":               `{"regions":["us-east-1"],"threshold":80}`,
+						"values_map.threshold": "80",
+						"values_map.regions":   `["us-east-1"]`,
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_OmitProductValues(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.accounts["123456789012"] = models.Account{
+		AccountID:     "123456789012",
+		CloudProvider: models.AWS,
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {Active: true, Values: map[string]any{"threshold": float64(80)}},
+		},
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/ZestyIamRole",
+			"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+		},
+	}
+	// SHA-256 of {"threshold":80}.
+	valuesHash := "6057186cf42c4d47046adbf882469f14e60e9f6c543999ecfd2c01b41fbf1f45"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "all" {}
+`,
+				Check: resource.TestCheckTypeSetElemNestedAttrs("data.zesty_accounts.all", "accounts.0.products.*", map[string]string{
+					"values":               "threshold: 80\n",
+					"values_map.threshold": "80",
+					"values_hash":          valuesHash,
+				}),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host                = %q
+  token               = "test-token"
+  values_format       = "json"
+  omit_product_values = true
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.products.#", "1"),
+					resource.TestCheckNoResourceAttr("data.zesty_accounts.all", "accounts.0.products.0.values"),
+					resource.TestCheckNoResourceAttr("data.zesty_accounts.all", "accounts.0.products.0.values_map.%"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.products.0.values_hash", valuesHash),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_ExternalID(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.accounts["123456789012"] = models.Account{
+		AccountID:     "123456789012",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/ZestyIamRole",
+			"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+		},
+	}
+	api.accounts["210987654321"] = models.Account{
+		AccountID:     "210987654321",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::210987654321:role/ZestyIamRole",
+			"externalID": "8d4a0c6e-3b52-4f0e-9d55-1f2b8a6c7e90",
+		},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "by_external_id" {
+  external_id = "8d4a0c6e-3b52-4f0e-9d55-1f2b8a6c7e90"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.by_external_id", "account_count", "1"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.by_external_id", "accounts.0.id", "210987654321"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.by_external_id", "accounts.0.role_arn", "arn:aws:iam::210987654321:role/ZestyIamRole"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "by_external_id" {
+  external_id = "00000000-0000-0000-0000-000000000000"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.by_external_id", "account_count", "0"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.by_external_id", "accounts.#", "0"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "by_external_id" {
+  external_id    = "8d4a0c6e-3b52-4f0e-9d55-1f2b8a6c7e90"
+  cloud_provider = "AWS"
+}
+`,
+				ExpectError: regexp.MustCompile(`Conflicting filters`),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_Region(t *testing.T) {
+	api, server := newTestAPI(t)
+	region := "eu-west-1"
+	additionalData := map[string]any{
+		"roleARN":    "arn:aws:iam::123456789012:role/ZestyIamRole",
+		"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+	}
+	api.accounts["123456789012"] = models.Account{
+		AccountID:      "123456789012",
+		CloudProvider:  models.AWS,
+		Region:         &region,
+		AdditionalData: additionalData,
+	}
+	api.accounts["210987654321"] = models.Account{
+		AccountID:      "210987654321",
+		CloudProvider:  models.AWS,
+		AdditionalData: additionalData,
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "all" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.id", "123456789012"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.region", "eu-west-1"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.1.id", "210987654321"),
+					resource.TestCheckNoResourceAttr("data.zesty_accounts.all", "accounts.1.region"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_CurAndAthena(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.accounts["123456789012"] = models.Account{
+		AccountID:     "123456789012",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/ZestyIamRole",
+			"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+		},
+		Cur:    &models.CurDetails{S3Bucket: "cur-bucket", ExportName: "cur-export", Type: "CUR2"},
+		Athena: &models.AthenaDetails{AthenaDB: "athena-db", AthenaRegion: "us-east-1"},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "all" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.cur.s3_bucket", "cur-bucket"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.cur.cur_export_name", "cur-export"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.cur.cur_type", "CUR2"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.athena.athena_db", "athena-db"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.athena.athena_region", "us-east-1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_MalformedAccount(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.accounts["123456789012"] = models.Account{
+		AccountID:     "123456789012",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/ZestyIamRole",
+			"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+		},
+	}
+	api.accounts["210987654321"] = models.Account{
+		AccountID:     "210987654321",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"externalID": "8d4a0c6e-3b52-4f0e-9d55-1f2b8a6c7e90",
+		},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "all" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.#", "1"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "account_count", "1"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.0.id", "123456789012"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "all" {
+  strict = true
+}
+`,
+				ExpectError: regexp.MustCompile(`Malformed account`),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_OnboardingStatus(t *testing.T) {
+	api, server := newTestAPI(t)
+	for id, status := range map[string]models.OnboardingStatus{
+		"123456789012": models.OnboardingCompleted,
+		"210987654321": "Failed",
+	} {
+		api.accounts[id] = models.Account{
+			AccountID:        id,
+			CloudProvider:    models.AWS,
+			OnboardingStatus: status,
+			AdditionalData: map[string]any{
+				"roleARN":    "arn:aws:iam::" + id + ":role/ZestyIamRole",
+				"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+			},
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "failed" {
+  onboarding_status = "Failed"
+}
+
+data "zesty_accounts" "pending" {
+  onboarding_status = "Pending"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.failed", "accounts.#", "1"),
+					resource.TestCheckResourceAttrPair("data.zesty_accounts.failed", "account_count", "data.zesty_accounts.failed", "accounts.#"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.failed", "accounts.0.id", "210987654321"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.failed", "accounts.0.ready", "false"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.pending", "accounts.#", "0"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.pending", "account_count", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_ReadyOnboardingStatuses(t *testing.T) {
+	api, server := newTestAPI(t)
+	for id, status := range map[string]models.OnboardingStatus{
+		"123456789012": models.OnboardingCompleted,
+		"210987654321": "Active",
+	} {
+		api.accounts[id] = models.Account{
+			AccountID:        id,
+			CloudProvider:    models.AWS,
+			OnboardingStatus: status,
+			AdditionalData: map[string]any{
+				"roleARN":    "arn:aws:iam::" + id + ":role/ZestyIamRole",
+				"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+			},
+		}
+	}
+	dataSources := `
+data "zesty_accounts" "completed" {
+  onboarding_status = "Completed"
+}
+
+data "zesty_accounts" "active" {
+  onboarding_status = "Active"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + dataSources,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.completed", "accounts.0.ready", "true"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.active", "accounts.0.ready", "false"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host                      = %q
+  token                     = "test-token"
+  ready_onboarding_statuses = ["Active"]
+}
+`, server.URL) + dataSources,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.completed", "accounts.0.ready", "false"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.active", "accounts.0.ready", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_Tags(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.accounts["123456789012"] = models.Account{
+		AccountID:     "123456789012",
+		CloudProvider: models.AWS,
+		Tags:          map[string]string{"team": "platform"},
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/ZestyIamRole",
+			"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+		},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "test" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.test", "accounts.0.tags.%", "1"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.test", "accounts.0.tags.team", "platform"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_UpdatedAfter(t *testing.T) {
+	api, server := newTestAPI(t)
+	for id, updatedAt := range map[string]time.Time{
+		"123456789012": time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		"210987654321": time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+	} {
+		api.accounts[id] = models.Account{
+			AccountID:     id,
+			CloudProvider: models.AWS,
+			UpdatedAt:     updatedAt,
+			AdditionalData: map[string]any{
+				"roleARN":    "arn:aws:iam::" + id + ":role/ZestyIamRole",
+				"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+			},
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "recent" {
+  updated_after = "2024-05-15T00:00:00Z"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.recent", "accounts.#", "1"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.recent", "accounts.0.id", "210987654321"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "recent" {
+  updated_after = "2024-05-15"
+}
+`,
+				ExpectError: regexp.MustCompile(`RFC3339`),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_Sort(t *testing.T) {
+	api, server := newTestAPI(t)
+	for _, account := range []models.Account{
+		{AccountID: "333333333333", CloudProvider: models.AWS, OnboardingStatus: "Failed"},
+		{AccountID: "111111111111", CloudProvider: models.GCP, OnboardingStatus: models.OnboardingCompleted},
+		{AccountID: "222222222222", CloudProvider: models.Azure, OnboardingStatus: "Pending"},
+	} {
+		account.AdditionalData = map[string]any{
+			"roleARN":    "arn:aws:iam::" + account.AccountID + ":role/ZestyIamRole",
+			"externalID": "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+		}
+		api.accounts[account.AccountID] = account
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "default" {}
+
+data "zesty_accounts" "id_desc" {
+  sort_by    = "id"
+  sort_order = "desc"
+}
+
+data "zesty_accounts" "cloud_provider" {
+  sort_by = "cloud_provider"
+}
+
+data "zesty_accounts" "onboarding_status" {
+  sort_by    = "onboarding_status"
+  sort_order = "desc"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_accounts.default", "accounts.0.id", "111111111111"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.default", "accounts.1.id", "222222222222"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.default", "accounts.2.id", "333333333333"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.id_desc", "accounts.0.id", "333333333333"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.id_desc", "accounts.2.id", "111111111111"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.cloud_provider", "accounts.0.cloud_provider", "AWS"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.cloud_provider", "accounts.1.cloud_provider", "Azure"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.cloud_provider", "accounts.2.cloud_provider", "GCP"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.onboarding_status", "accounts.0.onboarding_status", "Pending"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.onboarding_status", "accounts.1.onboarding_status", "Failed"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.onboarding_status", "accounts.2.onboarding_status", "Completed"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "invalid" {
+  sort_by = "region"
+}
+`,
+				ExpectError: regexp.MustCompile(`Value "region" is not supported`),
+			},
+		},
+	})
+}
+
+func TestAccAccountsDataSource_AdditionalDataJSON(t *testing.T) {
+	api, server := newTestAPI(t)
+	checkJSONAttr := func(key string, expected map[string]any) resource.TestCheckFunc {
+		return resource.TestCheckResourceAttrWith("data.zesty_accounts.all", key, func(value string) error {
+			var data map[string]any
+			if err := json.Unmarshal([]byte(value), &data); err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(expected, data) {
+				return fmt.Errorf("expected %s %v, got %v", key, expected, data)
+			}
+			return nil
+		})
+	}
+	api.accounts["123456789012"] = models.Account{
+		AccountID:     "123456789012",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"roleARN":     "arn:aws:iam::123456789012:role/ZestyIamRole",
+			"externalID":  "f1f0a7f7-a523-4197-9e19-ffd205a5bc20",
+			"clusterTier": "gold",
+			"integrations": map[string]any{
+				"slack": map[string]any{"channel": "#zesty", "Token": "xoxb-secret"},
+			},
+			"webhooks": []any{map[string]any{"url": "https://example.com/hook", "secret": "hook-secret"}},
+		},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_accounts" "all" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkJSONAttr("accounts.0.additional_data_json", map[string]any{
+						"roleARN":     "arn:aws:iam::123456789012:role/ZestyIamRole",
+						"clusterTier": "gold",
+						"integrations": map[string]any{
+							"slack": map[string]any{"channel": "#zesty"},
+						},
+						"webhooks": []any{map[string]any{"url": "https://example.com/hook"}},
+					}),
+					checkJSONAttr("accounts.0.additional_data", map[string]any{
+						"clusterTier": "gold",
+						"integrations": map[string]any{
+							"slack": map[string]any{"channel": "#zesty"},
+						},
+						"webhooks": []any{map[string]any{"url": "https://example.com/hook"}},
+					}),
+				),
+			},
+		},
+	})
+}
@@ -1,62 +1,108 @@
 package provider
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/zesty-co/terraform-provider-zesty/internal/models"
 	"gopkg.in/yaml.v3"
 )
 
-func ToModel(account *models.Account) (*accountModel, diag.Diagnostics) {
-	roleARN, exists := account.AdditionalData["roleARN"]
-	if !exists {
-		return nil, diag.Diagnostics{
-			diag.NewErrorDiagnostic(
-				"Missing role ARN for account",
-				"account.AdditionalData.roleARN is nil or empty",
-			),
-		}
+// Supported encodings of product values, selected with the provider's values_format attribute.
+const (
+	ValuesFormatYAML = "yaml"
+	ValuesFormatJSON = "json"
+)
+
+// ToModel converts an account returned by the API into the model of its account attribute. Product
+// values are encoded in valuesFormat, and the account is ready when its onboarding status is one of
+// readyStatuses.
+func ToModel(account *models.Account, valuesFormat string, readyStatuses []models.OnboardingStatus) (*accountModel, diag.Diagnostics) {
+	roleARN, diagnostic := additionalDataString(account.AdditionalData, "roleARN", "role ARN")
+	if diagnostic != nil {
+		return nil, diag.Diagnostics{diagnostic}
+	}
+	externalID, diagnostic := additionalDataString(account.AdditionalData, "externalID", "external ID")
+	if diagnostic != nil {
+		return nil, diag.Diagnostics{diagnostic}
 	}
+	return toModel(account, roleARN, externalID, valuesFormat, readyStatuses)
+}
 
-	roleARNString, ok := roleARN.(string)
-	if !ok {
-		return nil, diag.Diagnostics{
-			diag.NewErrorDiagnostic(
-				"Erroneous role ARN for account",
-				fmt.Sprintf("Expected string for role ARN but got %T", roleARN),
-			),
-		}
+// toModelBestEffort converts an account like ToModel, except that a missing or malformed role ARN or
+// external ID is a warning rather than an error, and is left empty. An incomplete account can then
+// still be imported, and fixed by applying the configuration.
+func toModelBestEffort(account *models.Account, valuesFormat string, readyStatuses []models.OnboardingStatus) (*accountModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	roleARN, warning := bestEffortAdditionalDataString(account.AdditionalData, "roleARN", "role ARN", "role_arn")
+	if warning != nil {
+		diags.Append(warning)
+	}
+	externalID, warning := bestEffortAdditionalDataString(account.AdditionalData, "externalID", "external ID", "external_id")
+	if warning != nil {
+		diags.Append(warning)
 	}
 
-	externalID, exists := account.AdditionalData["externalID"]
+	model, modelDiags := toModel(account, roleARN, externalID, valuesFormat, readyStatuses)
+	diags.Append(modelDiags...)
+	return model, diags
+}
+
+// additionalDataString returns the string stored under key in an account's additional data, or an
+// error diagnostic naming it label when it is missing or not a string.
+func additionalDataString(data map[string]any, key string, label string) (string, diag.Diagnostic) {
+	value, exists := data[key]
 	if !exists {
-		return nil, diag.Diagnostics{
-			diag.NewErrorDiagnostic(
-				"Missing external ID for account",
-				"account.AdditionalData.externalID is nil or empty",
-			),
-		}
+		return "", diag.NewErrorDiagnostic(
+			fmt.Sprintf("Missing %s for account", label),
+			fmt.Sprintf("account.AdditionalData.%s is nil or empty", key),
+		)
 	}
 
-	externalIDString, ok := externalID.(string)
+	valueString, ok := value.(string)
 	if !ok {
-		return nil, diag.Diagnostics{
-			diag.NewErrorDiagnostic(
-				"Erroneous external ID for account",
-				fmt.Sprintf("Expected string for external ID but got %T", roleARN),
-			),
-		}
+		return "", diag.NewErrorDiagnostic(
+			fmt.Sprintf("Erroneous %s for account", label),
+			fmt.Sprintf("Expected string for %s but got %T", label, value),
+		)
 	}
+	return valueString, nil
+}
 
-	rawValues := parseValues(account.AdditionalData)
-	valuesBytes, err := yaml.Marshal(rawValues)
+// bestEffortAdditionalDataString returns the string stored under key like additionalDataString. When
+// it is missing or malformed, it returns an empty string with a warning telling to set attribute in
+// the configuration.
+func bestEffortAdditionalDataString(data map[string]any, key string, label string, attribute string) (string, diag.Diagnostic) {
+	value, diagnostic := additionalDataString(data, key, label)
+	if diagnostic == nil {
+		return value, nil
+	}
+	return "", diag.NewWarningDiagnostic(
+		diagnostic.Summary(),
+		fmt.Sprintf("%s. The %s was left empty. Set %s in the configuration and apply to store it on the account.", diagnostic.Detail(), label, attribute),
+	)
+}
+
+// toModel converts an account into the model of its account attribute, with the given role ARN and
+// external ID.
+func toModel(account *models.Account, roleARN string, externalID string, valuesFormat string, readyStatuses []models.OnboardingStatus) (*accountModel, diag.Diagnostics) {
+	additionalData, err := additionalDataValue(withoutSensitiveKeys(withoutManagedKeys(account.AdditionalData)))
 	if err != nil {
 		return nil, diag.Diagnostics{
 			diag.NewErrorDiagnostic(
-				"Erroneous values from provider",
+				"Erroneous additional data from provider",
 				fmt.Sprintf("Got error: %v", err),
 			),
 		}
@@ -64,27 +110,27 @@ func ToModel(account *models.Account) (*accountModel, diag.Diagnostics) {
 
 	model := accountModel{
 		ID:               types.StringValue(account.AccountID),
+		OrganizationID:   organizationIDValue(account.OrganizationID),
 		Region:           types.StringPointerValue(account.Region),
 		CloudProvider:    types.StringValue(string(account.CloudProvider)),
-		RoleARN:          types.StringValue(roleARNString),
-		ExternalID:       types.StringValue(externalIDString),
+		RoleARN:          types.StringValue(roleARN),
+		ExternalID:       types.StringValue(externalID),
 		StorageClassName: types.StringValue(account.StorageClassName),
+		SubscriptionID:   optionalStringValue(account.SubscriptionID),
+		ProjectID:        optionalStringValue(account.ProjectID),
+		Tags:             tagsValue(account.Tags),
+		ScanCoverage:     parseScanCoverage(account.AdditionalData),
+		CreatedAt:        timeValue(account.CreatedAt),
+		UpdatedAt:        timeValue(account.UpdatedAt),
+		AdditionalData:   additionalData,
+		OnboardingStatus: onboardingStatusValue(account.OnboardingStatus),
+		Ready:            types.BoolValue(account.OnboardingStatus.ReadyIn(readyStatuses)),
 	}
 
-	var productNames []string
-	for name := range account.Products {
-		productNames = append(productNames, string(name))
-	}
-	sort.Strings(productNames)
-
-	model.Products = []productModel{}
-	for _, name := range productNames {
-		details := account.Products[models.Product(name)]
-		model.Products = append(model.Products, productModel{
-			Name:   types.StringValue(name),
-			Active: types.BoolValue(details.Active),
-			Values: types.StringValue(string(valuesBytes)),
-		})
+	var diags diag.Diagnostics
+	model.Products, diags = ProductsFromPayloadMap(account, valuesFormat)
+	if diags.HasError() {
+		return nil, diags
 	}
 	if account.Cur != nil {
 		model.Cur = &curModel{
@@ -105,7 +151,161 @@ func ToModel(account *models.Account) (*accountModel, diag.Diagnostics) {
 		}
 	}
 
-	return &model, nil
+	return &model, diags
+}
+
+// toPayload converts an account into the payload sent to the API. Additional data is left for the
+// caller to fill in.
+func toPayload(account accountModel) (models.Payload, diag.Diagnostics) {
+	cloudProvider, diags := parseCloudProviderAttribute(account.CloudProvider)
+	if diags.HasError() {
+		return models.Payload{}, diags
+	}
+
+	payload := models.Payload{
+		AccountID:        account.ID.ValueString(),
+		Region:           account.Region.ValueStringPointer(),
+		CloudProvider:    cloudProvider,
+		RoleARN:          account.RoleARN.ValueString(),
+		ExternalID:       account.ExternalID.ValueString(),
+		StorageClassName: account.StorageClassName.ValueString(),
+		OrganizationID:   account.OrganizationID.ValueInt64(),
+		SubscriptionID:   account.SubscriptionID.ValueString(),
+		ProjectID:        account.ProjectID.ValueString(),
+		Tags:             tagsPayload(account.Tags),
+	}
+
+	payload.Products, diags = ProductsToPayloadMap(account.Products, account.Region)
+	if diags.HasError() {
+		return models.Payload{}, diags
+	}
+
+	if account.Cur != nil {
+		payload.Cur = &models.CurDetails{
+			S3Bucket:   account.Cur.S3Bucket.ValueString(),
+			ExportName: account.Cur.ExportName.ValueString(),
+			Type:       account.Cur.Type.ValueString(),
+		}
+	}
+
+	if account.Athena != nil {
+		payload.Athena = &models.AthenaDetails{
+			AthenaDB:        account.Athena.AthenaDB.ValueString(),
+			AthenaS3Bucket:  account.Athena.AthenaS3Bucket.ValueString(),
+			AthenaProjectID: account.Athena.AthenaProjectID.ValueString(),
+			AthenaRegion:    account.Athena.AthenaRegion.ValueString(),
+			AthenaTable:     account.Athena.AthenaTable.ValueString(),
+			AthenaWorkgroup: account.Athena.AthenaWorkgroup.ValueString(),
+			AthenaCatalog:   account.Athena.AthenaCatalog.ValueString(),
+		}
+	}
+
+	return payload, nil
+}
+
+// ProductsToPayloadMap converts the products set of an account into the products map sent to the
+// API. Products in accountRegion are sent without a region of their own, so they follow later
+// changes to the account region. Values that are not valid YAML or JSON are reported as attribute
+// errors on the product. Deprecated product names are sent under their current names, with a
+// warning asking to update the configuration.
+func ProductsToPayloadMap(products []productModel, accountRegion types.String) (map[models.Product]models.ProductDetails, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	payload := map[models.Product]models.ProductDetails{}
+	for _, product := range products {
+		values, err := decodeValues(product.Values)
+		if err != nil {
+			diags.AddAttributeError(
+				productPath(product).AtName("values"),
+				"Invalid product values",
+				fmt.Sprintf("Could not parse values of product %s as YAML or JSON: %s", product.Name.ValueString(), err),
+			)
+			continue
+		}
+
+		region := productRegionOverride(product.Region, accountRegion)
+
+		name, deprecated := models.Product(product.Name.ValueString()).Canonical()
+		if deprecated {
+			diags.AddAttributeWarning(
+				productPath(product).AtName("name"),
+				"Deprecated product name",
+				fmt.Sprintf("Product %s has been renamed to %s. Update the configuration to use %s, as %s may stop being accepted in a future release.", product.Name.ValueString(), name, name, product.Name.ValueString()),
+			)
+		}
+
+		payload[name] = models.ProductDetails{
+			Active: product.Active.ValueBool(),
+			Values: values,
+			Region: region,
+		}
+	}
+	return payload, diags
+}
+
+// productAttributeTypes are the attribute types of an element of the products set.
+var productAttributeTypes = map[string]attr.Type{
+	"name":        types.StringType,
+	"active":      types.BoolType,
+	"values":      types.StringType,
+	"values_map":  types.MapType{ElemType: types.StringType},
+	"values_hash": types.StringType,
+	"region":      types.StringType,
+}
+
+// productPath returns the path of product in the products set, as configProducts does, or the path
+// of the whole set when the product cannot be converted to a set element.
+func productPath(product productModel) path.Path {
+	element, diags := types.ObjectValueFrom(context.Background(), productAttributeTypes, product)
+	if diags.HasError() {
+		return productsPath
+	}
+	return productsPath.AtSetValue(element)
+}
+
+// ProductsFromPayloadMap converts the products of an account returned by the API into the products
+// list, sorted by name. It is the inverse of ProductsToPayloadMap: products without a region of
+// their own get the account region, and products without values get the account-wide values.
+// Values that cannot be encoded are reported as warnings and left empty, so the rest of the account
+// can still be read.
+func ProductsFromPayloadMap(account *models.Account, valuesFormat string) ([]productModel, diag.Diagnostics) {
+	if valuesFormat != ValuesFormatYAML && valuesFormat != ValuesFormatJSON && valuesFormat != "" {
+		return nil, diag.Diagnostics{
+			diag.NewErrorDiagnostic(
+				"Erroneous values from provider",
+				fmt.Sprintf("Got error for unsupported values format %q", valuesFormat),
+			),
+		}
+	}
+
+	var diags diag.Diagnostics
+	var productNames []string
+	for name := range account.Products {
+		productNames = append(productNames, string(name))
+	}
+	sort.Strings(productNames)
+
+	products := []productModel{}
+	for _, name := range productNames {
+		details := account.Products[models.Product(name)]
+		values, err := productValues(account, details, valuesFormat)
+		if err != nil {
+			diags.AddWarning(
+				"Erroneous values from provider",
+				fmt.Sprintf("Could not encode values of product %s, leaving them empty: %s", name, err),
+			)
+			values = ""
+		}
+
+		products = append(products, productModel{
+			Name:       types.StringValue(name),
+			Active:     types.BoolValue(details.Active),
+			Values:     types.StringValue(values),
+			ValuesMap:  valuesMapValue(types.StringValue(values)),
+			ValuesHash: valuesHashValue(types.StringValue(values)),
+			Region:     productRegion(account, details),
+		})
+	}
+	return products, diags
 }
 
 func parseValues(input map[string]any) map[string]any {
@@ -126,3 +326,430 @@ func parseValues(input map[string]any) map[string]any {
 	}
 	return clean
 }
+
+// defaultAccountRegion is the region of accounts that do not set one.
+const defaultAccountRegion = "us-east-1"
+
+// productRegionOverride returns the region a product overrides the account region with, or nil when
+// the product runs in the account region, as it sets no region of its own or sets the account region
+// itself. A product region always takes precedence over the account region.
+func productRegionOverride(productRegion types.String, accountRegion types.String) *string {
+	if productRegion.IsUnknown() || productRegion.Equal(accountRegion) {
+		return nil
+	}
+	return productRegion.ValueStringPointer()
+}
+
+// productRegion returns the region of a product, falling back to the account region when the
+// product does not override it.
+func productRegion(account *models.Account, details models.ProductDetails) types.String {
+	if details.Region != nil {
+		return types.StringValue(*details.Region)
+	}
+	return types.StringPointerValue(account.Region)
+}
+
+// productValues encodes the values of a product in the given format. Products without values of
+// their own fall back to the account-wide values.
+func productValues(account *models.Account, details models.ProductDetails, valuesFormat string) (string, error) {
+	values := details.Values
+	if len(values) == 0 {
+		values = parseValues(account.AdditionalData)
+	}
+	return encodeValues(values, valuesFormat)
+}
+
+// encodeValues marshals values as JSON or YAML. Both can be decoded again with decodeValues.
+func encodeValues(values map[string]any, valuesFormat string) (string, error) {
+	var encoded []byte
+	var err error
+	switch valuesFormat {
+	case ValuesFormatJSON:
+		encoded, err = json.Marshal(values)
+	case ValuesFormatYAML, "":
+		encoded, err = yaml.Marshal(values)
+	default:
+		err = fmt.Errorf("unsupported values format %q", valuesFormat)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func parseScanCoverage(input map[string]any) types.Float64 {
+	switch coverage := input["scanCoverage"].(type) {
+	case float64:
+		return types.Float64Value(coverage)
+	default:
+		return types.Float64Null()
+	}
+}
+
+// managedAdditionalData lists the AdditionalData keys the provider sets through dedicated payload fields.
+var managedAdditionalData = []string{"roleARN", "externalID"}
+
+// withoutManagedKeys returns a copy of data without the managedAdditionalData keys, which are
+// exposed through their own attributes, such as the sensitive external_id.
+func withoutManagedKeys(data map[string]any) map[string]any {
+	unmanaged := make(map[string]any, len(data))
+	for key, value := range data {
+		if !slices.Contains(managedAdditionalData, key) {
+			unmanaged[key] = value
+		}
+	}
+	return unmanaged
+}
+
+// additionalDataValue encodes an account's AdditionalData as a JSON string, returning null when it is empty.
+func additionalDataValue(data map[string]any) (types.String, error) {
+	if len(data) == 0 {
+		return types.StringNull(), nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return types.StringNull(), err
+	}
+	return types.StringValue(string(encoded)), nil
+}
+
+// sensitiveAdditionalData lists the AdditionalData keys, compared case-insensitively, that
+// withoutSensitiveKeys removes.
+var sensitiveAdditionalData = []string{"apiKey", "externalID", "password", "secret", "token"}
+
+// withoutSensitiveKeys returns a copy of data without the sensitiveAdditionalData keys, removing them
+// from nested objects and lists too.
+func withoutSensitiveKeys(data map[string]any) map[string]any {
+	if data == nil {
+		return nil
+	}
+
+	stripped := make(map[string]any, len(data))
+	for key, value := range data {
+		if isSensitiveAdditionalData(key) {
+			continue
+		}
+		stripped[key] = withoutSensitiveValues(value)
+	}
+	return stripped
+}
+
+func withoutSensitiveValues(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		return withoutSensitiveKeys(value)
+	case []any:
+		stripped := make([]any, len(value))
+		for i, element := range value {
+			stripped[i] = withoutSensitiveValues(element)
+		}
+		return stripped
+	default:
+		return value
+	}
+}
+
+func isSensitiveAdditionalData(key string) bool {
+	for _, sensitive := range sensitiveAdditionalData {
+		if strings.EqualFold(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// unmanagedAdditionalData decodes the additional_data attribute and drops the keys the provider
+// manages itself, leaving the server-managed fields to echo back on update.
+func unmanagedAdditionalData(value types.String) (map[string]any, error) {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return nil, nil
+	}
+
+	data := map[string]any{}
+	if err := json.Unmarshal([]byte(value.ValueString()), &data); err != nil {
+		return nil, err
+	}
+	for _, key := range managedAdditionalData {
+		delete(data, key)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// parseCloudProviderAttribute parses the cloud_provider attribute of an account.
+func parseCloudProviderAttribute(value types.String) (models.CloudProvider, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	cloudProvider, err := models.ParseCloudProvider(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("account").AtName("cloud_provider"),
+			"Invalid cloud provider",
+			fmt.Sprintf("Could not parse cloud provider: %s", err),
+		)
+	}
+	return cloudProvider, diags
+}
+
+// organizationIDValue returns the organization ID, or null when the API did not return one.
+func organizationIDValue(id int64) types.Int64 {
+	if id == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(id)
+}
+
+// optionalStringValue returns s, or null when the API did not return a value.
+func optionalStringValue(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// tagsValue returns the tags of an account, or null when the account has none.
+func tagsValue(tags map[string]string) map[string]types.String {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	value := make(map[string]types.String, len(tags))
+	for k, v := range tags {
+		value[k] = types.StringValue(v)
+	}
+	return value
+}
+
+// tagsPayload converts the tags attribute into the tags sent to the API.
+func tagsPayload(tags map[string]types.String) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	payload := make(map[string]string, len(tags))
+	for k, v := range tags {
+		payload[k] = v.ValueString()
+	}
+	return payload
+}
+
+// preserveEmptyTags keeps an empty tags map from prior (the plan or the previous state) when the
+// API reports no tags, since tagsValue cannot tell an empty map from a missing one.
+func preserveEmptyTags(prior map[string]types.String, current map[string]types.String) map[string]types.String {
+	if current == nil && prior != nil && len(prior) == 0 {
+		return prior
+	}
+	return current
+}
+
+// preserveOrganizationID keeps the organization ID from prior (the plan or the previous state) when
+// the API does not report one. Unknown prior values, planned for accounts that omit organization_id,
+// become null.
+func preserveOrganizationID(prior types.Int64, current types.Int64) types.Int64 {
+	if current.IsNull() && !prior.IsUnknown() {
+		return prior
+	}
+	return current
+}
+
+// onboardingStatusValue returns the onboarding status, or null when the API did not return one.
+func onboardingStatusValue(status models.OnboardingStatus) types.String {
+	if status == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(string(status))
+}
+
+// timeValue formats t as RFC3339, returning null for the zero time.
+func timeValue(t time.Time) types.String {
+	if t.IsZero() {
+		return types.StringNull()
+	}
+	return types.StringValue(t.UTC().Format(time.RFC3339))
+}
+
+// lastUpdated reports when the account was last updated according to the API, falling back to
+// the local clock in UTC when the API does not return an update time.
+func lastUpdated(account *models.Account) types.String {
+	if account.UpdatedAt.IsZero() {
+		return types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	}
+	return timeValue(account.UpdatedAt)
+}
+
+// decodeValues parses a product's values attribute. Values may be written as YAML or JSON.
+func decodeValues(value types.String) (map[string]any, error) {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return nil, nil
+	}
+
+	values := map[string]any{}
+	if err := yaml.Unmarshal([]byte(value.ValueString()), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// valuesMapValue decodes a product's values attribute into its values_map attribute. Strings are kept
+// as they are, and other values, including nested objects and lists, are encoded as JSON. Values that
+// cannot be decoded give a null map.
+func valuesMapValue(value types.String) types.Map {
+	if value.IsUnknown() {
+		return types.MapUnknown(types.StringType)
+	}
+	values, err := decodeValues(value)
+	if value.IsNull() || err != nil {
+		return types.MapNull(types.StringType)
+	}
+
+	elements := map[string]attr.Value{}
+	for key, v := range values {
+		if s, ok := v.(string); ok {
+			elements[key] = types.StringValue(s)
+			continue
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return types.MapNull(types.StringType)
+		}
+		elements[key] = types.StringValue(string(encoded))
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// valuesHashValue returns the hex encoded SHA-256 checksum of a product's decoded values, so it
+// only changes with their content, not with their encoding, formatting or key order. Empty values
+// hash like an empty object, and values that cannot be decoded give a null checksum.
+func valuesHashValue(value types.String) types.String {
+	if value.IsUnknown() {
+		return types.StringUnknown()
+	}
+	values, err := decodeValues(value)
+	if value.IsNull() || err != nil {
+		return types.StringNull()
+	}
+	if values == nil {
+		values = map[string]any{}
+	}
+
+	// json.Marshal sorts map keys, which makes the encoding canonical.
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return types.StringNull()
+	}
+	sum := sha256.Sum256(encoded)
+	return types.StringValue(hex.EncodeToString(sum[:]))
+}
+
+// omitProductValues clears the values and values_map of products, keeping their values_hash, for
+// providers that set omit_product_values.
+func omitProductValues(products []productModel) {
+	for i := range products {
+		products[i].Values = types.StringNull()
+		products[i].ValuesMap = types.MapNull(types.StringType)
+	}
+}
+
+// keepProductAliases renames the products in current back to the deprecated names they have in
+// prior (the plan or the previous state), as the API reports products under their current names
+// but state must keep the names written in the configuration.
+func keepProductAliases(prior []productModel, current []productModel) {
+	aliases := map[models.Product]types.String{}
+	for _, product := range prior {
+		if name, deprecated := models.Product(product.Name.ValueString()).Canonical(); deprecated {
+			aliases[name] = product.Name
+		}
+	}
+
+	for i, product := range current {
+		if alias, ok := aliases[models.Product(product.Name.ValueString())]; ok {
+			current[i].Name = alias
+		}
+	}
+}
+
+// keepInactiveProducts adds the products that are inactive in prior (the plan or the previous
+// state) but missing from current, so a product switched off in the configuration is reported
+// as inactive rather than absent when the API leaves inactive products out of its responses.
+// Products without a known region of their own get accountRegion.
+func keepInactiveProducts(prior []productModel, current []productModel, accountRegion types.String, valuesFormat string) []productModel {
+	reported := map[string]bool{}
+	for _, product := range current {
+		reported[product.Name.ValueString()] = true
+	}
+
+	for _, product := range prior {
+		if product.Active.IsUnknown() || product.Active.ValueBool() || reported[product.Name.ValueString()] {
+			continue
+		}
+
+		values := product.Values
+		if values.IsNull() || values.IsUnknown() {
+			encoded, _ := encodeValues(map[string]any{}, valuesFormat)
+			values = types.StringValue(encoded)
+		}
+		region := product.Region
+		if region.IsNull() || region.IsUnknown() {
+			region = accountRegion
+		}
+		current = append(current, productModel{
+			Name:       product.Name,
+			Active:     types.BoolValue(false),
+			Values:     values,
+			ValuesMap:  valuesMapValue(values),
+			ValuesHash: valuesHashValue(values),
+			Region:     region,
+		})
+	}
+	return current
+}
+
+// keepUnconfiguredValues sets the values of the products of payload whose planned values are unknown,
+// as they are not configured, to their values in prior, so updates leave the values the API manages
+// unchanged instead of clearing them.
+func keepUnconfiguredValues(planned []productModel, payload map[models.Product]models.ProductDetails, prior map[models.Product]models.ProductDetails) {
+	for _, product := range planned {
+		if !product.Values.IsUnknown() {
+			continue
+		}
+		name, _ := models.Product(product.Name.ValueString()).Canonical()
+		details, ok := payload[name]
+		priorDetails, priorOK := prior[name]
+		if !ok || !priorOK {
+			continue
+		}
+		details.Values = priorDetails.Values
+		payload[name] = details
+	}
+}
+
+// preserveValues keeps the values strings from prior (the plan or the previous state) when they
+// decode to the same content as the values read from the API, so formatting differences between
+// the configuration and the API encoding don't show up as diffs.
+func preserveValues(prior []productModel, current []productModel) {
+	priorValues := map[string]types.String{}
+	for _, product := range prior {
+		priorValues[product.Name.ValueString()] = product.Values
+	}
+
+	for i, product := range current {
+		previous, ok := priorValues[product.Name.ValueString()]
+		if !ok || previous.IsNull() || previous.IsUnknown() || previous.Equal(product.Values) {
+			continue
+		}
+
+		previousValues, err := decodeValues(previous)
+		if err != nil {
+			continue
+		}
+		currentValues, err := decodeValues(product.Values)
+		if err != nil {
+			continue
+		}
+		if reflect.DeepEqual(previousValues, currentValues) {
+			current[i].Values = previous
+		}
+	}
+}
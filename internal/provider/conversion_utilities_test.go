@@ -1,8 +1,15 @@
 package provider_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -90,7 +97,7 @@ func TestToModel(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			model, diags := provider.ToModel(tt.account)
+			model, diags := provider.ToModel(tt.account, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
 			if tt.expectedErrorMsg != "" {
 				require.True(t, diags.HasError())
 				require.Len(t, diags, 1)
@@ -108,3 +115,695 @@ func TestToModel(t *testing.T) {
 		})
 	}
 }
+
+func TestToModel_ScanCoverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]any
+		expected types.Float64
+	}{
+		{
+			name:     "scanned account",
+			data:     map[string]any{"scanCoverage": 87.5},
+			expected: types.Float64Value(87.5),
+		},
+		{
+			name:     "whole coverage",
+			data:     map[string]any{"scanCoverage": 100.0},
+			expected: types.Float64Value(100),
+		},
+		{
+			name:     "unscanned account",
+			data:     map[string]any{},
+			expected: types.Float64Null(),
+		},
+		{
+			name:     "null coverage",
+			data:     map[string]any{"scanCoverage": nil},
+			expected: types.Float64Null(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.data["roleARN"] = "arn:aws:iam::123456789012:role/example"
+			tt.data["externalID"] = "external-id"
+
+			model, diags := provider.ToModel(&models.Account{
+				AccountID:      "acc",
+				CloudProvider:  models.AWS,
+				AdditionalData: tt.data,
+			}, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+			require.False(t, diags.HasError())
+			require.NotNil(t, model)
+			assert.Equal(t, tt.expected, model.ScanCoverage)
+		})
+	}
+}
+
+func TestToModel_Timestamps(t *testing.T) {
+	tests := []struct {
+		name              string
+		createdAt         time.Time
+		updatedAt         time.Time
+		expectedCreatedAt types.String
+		expectedUpdatedAt types.String
+	}{
+		{
+			name:              "timestamps are formatted as RFC3339",
+			createdAt:         time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+			updatedAt:         time.Date(2024, 3, 2, 8, 15, 45, 0, time.UTC),
+			expectedCreatedAt: types.StringValue("2024-03-01T10:30:00Z"),
+			expectedUpdatedAt: types.StringValue("2024-03-02T08:15:45Z"),
+		},
+		{
+			name:              "non UTC timestamps are normalized",
+			createdAt:         time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("IST", 2*60*60)),
+			updatedAt:         time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("IST", 2*60*60)),
+			expectedCreatedAt: types.StringValue("2024-03-01T10:30:00Z"),
+			expectedUpdatedAt: types.StringValue("2024-03-01T10:30:00Z"),
+		},
+		{
+			name:              "zero timestamps are null",
+			expectedCreatedAt: types.StringNull(),
+			expectedUpdatedAt: types.StringNull(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, diags := provider.ToModel(&models.Account{
+				AccountID:     "acc",
+				CloudProvider: models.AWS,
+				AdditionalData: map[string]any{
+					"roleARN":    "arn:aws:iam::123456789012:role/example",
+					"externalID": "external-id",
+				},
+				CreatedAt: tt.createdAt,
+				UpdatedAt: tt.updatedAt,
+			}, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+			require.False(t, diags.HasError())
+			require.NotNil(t, model)
+			assert.Equal(t, tt.expectedCreatedAt, model.CreatedAt)
+			assert.Equal(t, tt.expectedUpdatedAt, model.UpdatedAt)
+		})
+	}
+}
+
+func TestToModel_Ready(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         models.OnboardingStatus
+		readyStatuses  []models.OnboardingStatus
+		expectedStatus types.String
+		expectedReady  types.Bool
+	}{
+		{
+			name:           "completed onboarding is ready",
+			status:         models.OnboardingCompleted,
+			expectedStatus: types.StringValue("Completed"),
+			expectedReady:  types.BoolValue(true),
+		},
+		{
+			name:           "pending onboarding is not ready",
+			status:         models.OnboardingStatus("Pending"),
+			expectedStatus: types.StringValue("Pending"),
+			expectedReady:  types.BoolValue(false),
+		},
+		{
+			name:           "missing status is not ready",
+			expectedStatus: types.StringNull(),
+			expectedReady:  types.BoolValue(false),
+		},
+		{
+			name:           "configured ready status is ready",
+			status:         models.OnboardingStatus("Active"),
+			readyStatuses:  []models.OnboardingStatus{"Active"},
+			expectedStatus: types.StringValue("Active"),
+			expectedReady:  types.BoolValue(true),
+		},
+		{
+			name:           "completed onboarding is not ready when not configured",
+			status:         models.OnboardingCompleted,
+			readyStatuses:  []models.OnboardingStatus{"Active"},
+			expectedStatus: types.StringValue("Completed"),
+			expectedReady:  types.BoolValue(false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readyStatuses := models.ReadyOnboardingStatuses
+			if tt.readyStatuses != nil {
+				readyStatuses = tt.readyStatuses
+			}
+			model, diags := provider.ToModel(&models.Account{
+				AccountID:        "acc",
+				CloudProvider:    models.AWS,
+				OnboardingStatus: tt.status,
+				AdditionalData: map[string]any{
+					"roleARN":    "arn:aws:iam::123456789012:role/example",
+					"externalID": "external-id",
+				},
+			}, provider.ValuesFormatYAML, readyStatuses)
+			require.False(t, diags.HasError())
+			require.NotNil(t, model)
+			assert.Equal(t, tt.expectedStatus, model.OnboardingStatus)
+			assert.Equal(t, tt.expectedReady, model.Ready)
+		})
+	}
+}
+
+func TestToModel_ProductRegion(t *testing.T) {
+	accountRegion := "us-east-1"
+	productRegion := "eu-west-1"
+	model, diags := provider.ToModel(&models.Account{
+		AccountID:     "acc",
+		CloudProvider: models.AWS,
+		Region:        &accountRegion,
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass:   {Active: true},
+			models.ZestyDisk: {Active: true, Region: &productRegion},
+		},
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/example",
+			"externalID": "external-id",
+		},
+	}, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+	require.False(t, diags.HasError())
+	require.NotNil(t, model)
+
+	require.Len(t, model.Products, 2)
+	assert.Equal(t, types.StringValue("Kompass"), model.Products[0].Name)
+	assert.Equal(t, types.StringValue("us-east-1"), model.Products[0].Region)
+	assert.Equal(t, types.StringValue("ZestyDisk"), model.Products[1].Name)
+	assert.Equal(t, types.StringValue("eu-west-1"), model.Products[1].Region)
+}
+
+func TestToModel_OrganizationID(t *testing.T) {
+	tests := []struct {
+		name           string
+		organizationID int64
+		expectedResult types.Int64
+	}{
+		{name: "account in an organization", organizationID: 42, expectedResult: types.Int64Value(42)},
+		{name: "account without organization", expectedResult: types.Int64Null()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, diags := provider.ToModel(&models.Account{
+				AccountID:      "acc",
+				CloudProvider:  models.AWS,
+				OrganizationID: tt.organizationID,
+				AdditionalData: map[string]any{
+					"roleARN":    "arn:aws:iam::123456789012:role/example",
+					"externalID": "external-id",
+				},
+			}, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+			require.False(t, diags.HasError())
+			require.NotNil(t, model)
+			assert.Equal(t, tt.expectedResult, model.OrganizationID)
+		})
+	}
+}
+
+func TestToModel_SubscriptionID(t *testing.T) {
+	tests := []struct {
+		name           string
+		account        models.Account
+		expectedResult types.String
+	}{
+		{
+			name: "Azure account with subscription",
+			account: models.Account{
+				AccountID:      "acc",
+				CloudProvider:  models.Azure,
+				SubscriptionID: "6f1c2a9e-4b7d-4c1e-9a3f-2d8e5b7c1a04",
+			},
+			expectedResult: types.StringValue("6f1c2a9e-4b7d-4c1e-9a3f-2d8e5b7c1a04"),
+		},
+		{
+			name: "AWS account without subscription",
+			account: models.Account{
+				AccountID:     "acc",
+				CloudProvider: models.AWS,
+			},
+			expectedResult: types.StringNull(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.account.AdditionalData = map[string]any{
+				"roleARN":    "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/zesty",
+				"externalID": "external-id",
+			}
+			model, diags := provider.ToModel(&tt.account, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+			require.False(t, diags.HasError())
+			require.NotNil(t, model)
+			assert.Equal(t, tt.expectedResult, model.SubscriptionID)
+		})
+	}
+}
+
+func TestToModel_ProjectID(t *testing.T) {
+	tests := []struct {
+		name           string
+		account        models.Account
+		expectedResult types.String
+	}{
+		{
+			name: "GCP account with project",
+			account: models.Account{
+				AccountID:     "acc",
+				CloudProvider: models.GCP,
+				ProjectID:     "zesty-prod-123",
+			},
+			expectedResult: types.StringValue("zesty-prod-123"),
+		},
+		{
+			name: "Azure account without project",
+			account: models.Account{
+				AccountID:     "acc",
+				CloudProvider: models.Azure,
+			},
+			expectedResult: types.StringNull(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.account.AdditionalData = map[string]any{
+				"roleARN":    "zesty@zesty-prod-123.iam.gserviceaccount.com",
+				"externalID": "external-id",
+			}
+			model, diags := provider.ToModel(&tt.account, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+			require.False(t, diags.HasError())
+			require.NotNil(t, model)
+			assert.Equal(t, tt.expectedResult, model.ProjectID)
+		})
+	}
+}
+
+func TestToModel_Tags(t *testing.T) {
+	account := models.Account{
+		AccountID:      "acc",
+		CloudProvider:  models.AWS,
+		AdditionalData: map[string]any{"roleARN": "arn:aws", "externalID": "ext"},
+	}
+
+	model, diags := provider.ToModel(&account, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+	require.False(t, diags.HasError())
+	assert.Nil(t, model.Tags)
+
+	account.Tags = map[string]string{"team": "platform", "cost-center": "1234"}
+	model, diags = provider.ToModel(&account, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+	require.False(t, diags.HasError())
+	assert.Equal(t, map[string]types.String{
+		"team":        types.StringValue("platform"),
+		"cost-center": types.StringValue("1234"),
+	}, model.Tags)
+}
+
+func TestToModel_ProductValues(t *testing.T) {
+	model, diags := provider.ToModel(&models.Account{
+		AccountID:     "acc",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/example",
+			"externalID": "external-id",
+			"values":     map[string]any{"accountWide": true},
+		},
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {Active: true, Values: map[string]any{"threshold": 80}},
+			models.CM:      {Active: true},
+		},
+	}, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+	require.False(t, diags.HasError())
+	require.Len(t, model.Products, 2)
+
+	assert.Equal(t, types.StringValue("CM"), model.Products[0].Name)
+	assert.Equal(t, types.StringValue("accountWide: true\n"), model.Products[0].Values)
+	assert.Equal(t, types.StringValue("Kompass"), model.Products[1].Name)
+	assert.Equal(t, types.StringValue("threshold: 80\n"), model.Products[1].Values)
+}
+
+func TestToModel_ProductValuesHash(t *testing.T) {
+	account := func(values map[string]any) *models.Account {
+		return &models.Account{
+			AccountID:     "acc",
+			CloudProvider: models.AWS,
+			AdditionalData: map[string]any{
+				"roleARN":    "arn:aws:iam::123456789012:role/example",
+				"externalID": "external-id",
+			},
+			Products: map[models.Product]models.ProductDetails{
+				models.Kompass: {Active: true, Values: values},
+			},
+		}
+	}
+	valuesHash := func(t *testing.T, values map[string]any, valuesFormat string) string {
+		model, diags := provider.ToModel(account(values), valuesFormat, models.ReadyOnboardingStatuses)
+		require.False(t, diags.HasError())
+		require.Len(t, model.Products, 1)
+		return model.Products[0].ValuesHash.ValueString()
+	}
+
+	values := map[string]any{
+		"threshold": 80,
+		"regions":   []any{"us-east-1", "eu-west-1"},
+		"limits":    map[string]any{"cpu": "2", "memory": 4096},
+	}
+	hash := valuesHash(t, values, provider.ValuesFormatYAML)
+	assert.Regexp(t, `^[0-9a-f]{64}$`, hash)
+
+	t.Run("stable across reads", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, hash, valuesHash(t, values, provider.ValuesFormatYAML))
+		}
+	})
+
+	t.Run("independent of values format", func(t *testing.T) {
+		assert.Equal(t, hash, valuesHash(t, values, provider.ValuesFormatJSON))
+	})
+
+	t.Run("changes with content", func(t *testing.T) {
+		changed := map[string]any{
+			"threshold": 81,
+			"regions":   []any{"us-east-1", "eu-west-1"},
+			"limits":    map[string]any{"cpu": "2", "memory": 4096},
+		}
+		assert.NotEqual(t, hash, valuesHash(t, changed, provider.ValuesFormatYAML))
+
+		reordered := map[string]any{
+			"threshold": 80,
+			"regions":   []any{"eu-west-1", "us-east-1"},
+			"limits":    map[string]any{"cpu": "2", "memory": 4096},
+		}
+		assert.NotEqual(t, hash, valuesHash(t, reordered, provider.ValuesFormatYAML))
+	})
+
+	t.Run("empty values", func(t *testing.T) {
+		// SHA-256 of {}.
+		assert.Equal(t, "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", valuesHash(t, nil, provider.ValuesFormatYAML))
+		assert.Equal(t, "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", valuesHash(t, nil, provider.ValuesFormatJSON))
+	})
+}
+
+func TestToModel_ProductValuesMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   map[string]any
+		expected map[string]string
+	}{
+		{
+			name:     "flat values",
+			values:   map[string]any{"mode": "lite", "threshold": 80, "enabled": true},
+			expected: map[string]string{"mode": "lite", "threshold": "80", "enabled": "true"},
+		},
+		{
+			name: "nested values",
+			values: map[string]any{
+				"cluster": map[string]any{"name": "prod", "nodes": 3},
+				"zones":   []any{"a", "b"},
+			},
+			expected: map[string]string{
+				"cluster": `{"name":"prod","nodes":3}`,
+				"zones":   `["a","b"]`,
+			},
+		},
+		{
+			name:     "no values",
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, format := range []string{provider.ValuesFormatYAML, provider.ValuesFormatJSON} {
+				model, diags := provider.ToModel(&models.Account{
+					AccountID:     "acc",
+					CloudProvider: models.AWS,
+					AdditionalData: map[string]any{
+						"roleARN":    "arn:aws:iam::123456789012:role/example",
+						"externalID": "external-id",
+					},
+					Products: map[models.Product]models.ProductDetails{
+						models.Kompass: {Active: true, Values: tt.values},
+					},
+				}, format, models.ReadyOnboardingStatuses)
+				require.False(t, diags.HasError())
+				require.Len(t, model.Products, 1)
+
+				valuesMap := map[string]string{}
+				require.False(t, model.Products[0].ValuesMap.ElementsAs(context.Background(), &valuesMap, false).HasError())
+				assert.Equal(t, tt.expected, valuesMap, format)
+			}
+		})
+	}
+}
+
+func TestToModel_AdditionalData(t *testing.T) {
+	model, diags := provider.ToModel(&models.Account{
+		AccountID:     "acc",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/example",
+			"externalID": "external-id",
+			"diskConfig": map[string]any{"maxSize": 100, "apiKey": "disk-api-key"},
+		},
+	}, provider.ValuesFormatYAML, models.ReadyOnboardingStatuses)
+	require.False(t, diags.HasError())
+	require.NotNil(t, model)
+	assert.JSONEq(t, `{"diskConfig": {"maxSize": 100}}`, model.AdditionalData.ValueString())
+	assert.Equal(t, "arn:aws:iam::123456789012:role/example", model.RoleARN.ValueString())
+	assert.Equal(t, "external-id", model.ExternalID.ValueString())
+}
+
+func TestToModel_ValuesFormatJSON(t *testing.T) {
+	values := map[string]any{
+		"threshold": float64(80),
+		"regions":   []any{"us-east-1", "eu-west-1"},
+		"limits": map[string]any{
+			"cpu":    "2",
+			"memory": map[string]any{"max": float64(4096), "units": []any{"Mi"}},
+		},
+	}
+
+	model, diags := provider.ToModel(&models.Account{
+		AccountID:     "acc",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/example",
+			"externalID": "external-id",
+			"values":     map[string]any{"accountWide": true},
+		},
+		Products: map[models.Product]models.ProductDetails{
+			models.CM:      {Active: true},
+			models.Kompass: {Active: true, Values: values},
+		},
+	}, provider.ValuesFormatJSON, models.ReadyOnboardingStatuses)
+	require.False(t, diags.HasError())
+	require.NotNil(t, model)
+	require.Len(t, model.Products, 2)
+
+	assert.JSONEq(t, `{"accountWide":true}`, model.Products[0].Values.ValueString())
+
+	encoded := model.Products[1].Values.ValueString()
+	require.True(t, json.Valid([]byte(encoded)), encoded)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(encoded), &decoded))
+	assert.Equal(t, values, decoded)
+}
+
+func TestToModel_InvalidValuesFormat(t *testing.T) {
+	_, diags := provider.ToModel(&models.Account{
+		AccountID:     "acc",
+		CloudProvider: models.AWS,
+		AdditionalData: map[string]any{
+			"roleARN":    "arn:aws:iam::123456789012:role/example",
+			"externalID": "external-id",
+		},
+		Products: map[models.Product]models.ProductDetails{
+			models.Kompass: {Active: true},
+		},
+	}, "toml", models.ReadyOnboardingStatuses)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Detail(), `unsupported values format "toml"`)
+}
+
+// unencodableValue fails to marshal as either YAML or JSON.
+type unencodableValue struct{}
+
+func (unencodableValue) MarshalYAML() (any, error) { return nil, errors.New("unencodable value") }
+
+func (unencodableValue) MarshalJSON() ([]byte, error) { return nil, errors.New("unencodable value") }
+
+func TestToModel_UnencodableValues(t *testing.T) {
+	for _, valuesFormat := range []string{provider.ValuesFormatYAML, provider.ValuesFormatJSON} {
+		t.Run(valuesFormat, func(t *testing.T) {
+			model, diags := provider.ToModel(&models.Account{
+				AccountID:     "acc",
+				CloudProvider: models.AWS,
+				AdditionalData: map[string]any{
+					"roleARN":    "arn:aws:iam::123456789012:role/example",
+					"externalID": "external-id",
+				},
+				Products: map[models.Product]models.ProductDetails{
+					models.CM:      {Active: true, Values: map[string]any{"term": "1y"}},
+					models.Kompass: {Active: true, Values: map[string]any{"broken": unencodableValue{}}},
+				},
+			}, valuesFormat, models.ReadyOnboardingStatuses)
+			require.False(t, diags.HasError())
+			require.Len(t, diags, 1)
+			assert.Equal(t, diag.SeverityWarning, diags[0].Severity())
+			assert.Contains(t, diags[0].Detail(), "product Kompass")
+			assert.Contains(t, diags[0].Detail(), "unencodable value")
+
+			require.NotNil(t, model)
+			assert.Equal(t, "acc", model.ID.ValueString())
+			assert.Equal(t, "arn:aws:iam::123456789012:role/example", model.RoleARN.ValueString())
+			require.Len(t, model.Products, 2)
+			assert.Equal(t, "CM", model.Products[0].Name.ValueString())
+			assert.NotEmpty(t, model.Products[0].Values.ValueString())
+			assert.Equal(t, "Kompass", model.Products[1].Name.ValueString())
+			assert.True(t, model.Products[1].Active.ValueBool())
+			assert.Equal(t, "", model.Products[1].Values.ValueString())
+		})
+	}
+}
+
+func TestProductsFromPayloadMap(t *testing.T) {
+	region := "us-east-1"
+	override := "eu-west-1"
+
+	tests := []struct {
+		name            string
+		products        map[models.Product]models.ProductDetails
+		expectedNames   []string
+		expectedRegions []string
+	}{
+		{
+			name:     "no products",
+			products: nil,
+		},
+		{
+			name: "multiple products sorted by name",
+			products: map[models.Product]models.ProductDetails{
+				models.ZestyDisk: {Active: false, Values: map[string]any{"mode": "lite"}},
+				models.CM:        {Active: true, Region: &override},
+				models.Kompass:   {Active: true, Values: map[string]any{"cluster": "prod"}},
+			},
+			expectedNames:   []string{"CM", "Kompass", "ZestyDisk"},
+			expectedRegions: []string{override, region, region},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := &models.Account{AccountID: "acc", Region: &region, Products: tt.products}
+			products, diags := provider.ProductsFromPayloadMap(account, provider.ValuesFormatJSON)
+			require.Empty(t, diags)
+			require.NotNil(t, products)
+			require.Len(t, products, len(tt.expectedNames))
+
+			for i, product := range products {
+				assert.Equal(t, tt.expectedNames[i], product.Name.ValueString())
+				assert.Equal(t, tt.expectedRegions[i], product.Region.ValueString())
+				assert.Equal(t, tt.products[models.Product(tt.expectedNames[i])].Active, product.Active.ValueBool())
+			}
+		})
+	}
+}
+
+func TestProductsToPayloadMap(t *testing.T) {
+	region := "us-east-1"
+	override := "eu-west-1"
+
+	// productPath returns the path of product in the products set of the account resource.
+	productPath := func(t *testing.T, product any) path.Path {
+		element, diags := types.ObjectValueFrom(context.Background(), map[string]attr.Type{
+			"name":        types.StringType,
+			"active":      types.BoolType,
+			"values":      types.StringType,
+			"values_map":  types.MapType{ElemType: types.StringType},
+			"values_hash": types.StringType,
+			"region":      types.StringType,
+		}, product)
+		require.False(t, diags.HasError(), diags)
+		return path.Root("account").AtName("products").AtSetValue(element)
+	}
+
+	t.Run("no products", func(t *testing.T) {
+		payload, diags := provider.ProductsToPayloadMap(nil, types.StringValue(region))
+		require.False(t, diags.HasError())
+		assert.Empty(t, payload)
+		assert.NotNil(t, payload)
+	})
+
+	t.Run("round trip of multiple products", func(t *testing.T) {
+		expected := map[models.Product]models.ProductDetails{
+			models.Kompass:   {Active: true, Values: map[string]any{"cluster": "prod"}},
+			models.CM:        {Active: true, Values: map[string]any{"term": "1y"}, Region: &override},
+			models.ZestyDisk: {Active: false, Values: map[string]any{"mode": "lite"}},
+		}
+		account := &models.Account{AccountID: "acc", Region: &region, Products: expected}
+
+		products, diags := provider.ProductsFromPayloadMap(account, provider.ValuesFormatYAML)
+		require.Empty(t, diags)
+
+		payload, diags := provider.ProductsToPayloadMap(products, types.StringValue(region))
+		require.False(t, diags.HasError())
+		assert.Equal(t, expected, payload)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		account := &models.Account{
+			AccountID: "acc",
+			Region:    &region,
+			Products: map[models.Product]models.ProductDetails{
+				models.CM:      {Active: true},
+				models.Kompass: {Active: true},
+			},
+		}
+		products, diags := provider.ProductsFromPayloadMap(account, provider.ValuesFormatYAML)
+		require.Empty(t, diags)
+		products[1].Values = types.StringValue("cluster: [prod")
+
+		_, diags = provider.ProductsToPayloadMap(products, types.StringValue(region))
+		require.True(t, diags.HasError())
+		assert.Equal(t, "Invalid product values", diags[0].Summary())
+		assert.Contains(t, diags[0].Detail(), "product Kompass")
+		require.Implements(t, (*diag.DiagnosticWithPath)(nil), diags[0])
+		assert.Equal(t, productPath(t, products[1]).AtName("values"), diags[0].(diag.DiagnosticWithPath).Path())
+	})
+
+	t.Run("deprecated product name", func(t *testing.T) {
+		original := models.ProductAliases
+		models.ProductAliases = map[models.Product]models.Product{"CostManagement": models.CM}
+		t.Cleanup(func() { models.ProductAliases = original })
+
+		account := &models.Account{
+			AccountID: "acc",
+			Region:    &region,
+			Products: map[models.Product]models.ProductDetails{
+				"CostManagement": {Active: true, Values: map[string]any{"term": "1y"}},
+			},
+		}
+		products, diags := provider.ProductsFromPayloadMap(account, provider.ValuesFormatYAML)
+		require.Empty(t, diags)
+
+		payload, diags := provider.ProductsToPayloadMap(products, types.StringValue(region))
+		require.False(t, diags.HasError())
+		assert.Equal(t, map[models.Product]models.ProductDetails{
+			models.CM: {Active: true, Values: map[string]any{"term": "1y"}},
+		}, payload)
+		require.Len(t, diags.Warnings(), 1)
+		assert.Equal(t, "Deprecated product name", diags[0].Summary())
+		assert.Contains(t, diags[0].Detail(), "Product CostManagement has been renamed to CM")
+		require.Implements(t, (*diag.DiagnosticWithPath)(nil), diags[0])
+		assert.Equal(t, productPath(t, products[0]).AtName("name"), diags[0].(diag.DiagnosticWithPath).Path())
+	})
+}
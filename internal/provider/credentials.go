@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultCredentialsFile is the path of the shared credentials file, relative to the home directory.
+var defaultCredentialsFile = filepath.Join(".zesty", "credentials")
+
+// credentialsProfile holds the settings of a single profile in the shared credentials file.
+type credentialsProfile struct {
+	Host  string `json:"host"`
+	Token string `json:"token"`
+}
+
+// credentialsFilePath resolves the credentials file path, expanding a leading ~ and falling back to
+// defaultCredentialsFile in the home directory when file is empty.
+func credentialsFilePath(file string) (string, error) {
+	if file != "" && file != "~" && !strings.HasPrefix(file, "~/") {
+		return file, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if file == "" {
+		return filepath.Join(home, defaultCredentialsFile), nil
+	}
+	return filepath.Join(home, strings.TrimPrefix(file, "~")), nil
+}
+
+// readTokenFile reads a token from file, expanding a leading ~ and trimming surrounding whitespace.
+func readTokenFile(file string) (string, error) {
+	file, err := credentialsFilePath(file)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("%s is empty", file)
+	}
+	return token, nil
+}
+
+// readCredentialsProfile resolves the credentials file path and reads profile from it.
+func readCredentialsProfile(file string, profile string) (credentialsProfile, error) {
+	file, err := credentialsFilePath(file)
+	if err != nil {
+		return credentialsProfile{}, err
+	}
+	return loadCredentialsProfile(file, profile)
+}
+
+// loadCredentialsProfile reads profile from the shared credentials file. The file is either a JSON
+// object keyed by profile name, or INI with one [profile] section per profile:
+//
+//	[default]
+//	host  = https://api.zesty.co/kompass-platform
+//	token = ...
+func loadCredentialsProfile(file string, profile string) (credentialsProfile, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return credentialsProfile{}, err
+	}
+
+	var profiles map[string]credentialsProfile
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		err = json.Unmarshal(content, &profiles)
+	} else {
+		profiles, err = parseINICredentials(content)
+	}
+	if err != nil {
+		return credentialsProfile{}, fmt.Errorf("parsing %s: %w", file, err)
+	}
+
+	credentials, ok := profiles[profile]
+	if !ok {
+		return credentialsProfile{}, fmt.Errorf("profile %q not found in %s", profile, file)
+	}
+	return credentials, nil
+}
+
+func parseINICredentials(content []byte) (map[string]credentialsProfile, error) {
+	profiles := map[string]credentialsProfile{}
+	section := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			profiles[section] = credentialsProfile{}
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", line)
+		}
+		if section == "" {
+			return nil, fmt.Errorf("line %d: key outside of a [profile] section", line)
+		}
+
+		credentials := profiles[section]
+		switch strings.TrimSpace(key) {
+		case "host":
+			credentials.Host = strings.TrimSpace(value)
+		case "token":
+			credentials.Token = strings.TrimSpace(value)
+		}
+		profiles[section] = credentials
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+)
+
+// HealthDataSource checks connectivity to the Zesty API by validating the token, without reading or
+// changing any account.
+type HealthDataSource struct {
+	client *client.Client
+}
+
+var (
+	_ datasource.DataSource              = &HealthDataSource{}
+	_ datasource.DataSourceWithConfigure = &HealthDataSource{}
+)
+
+func NewHealthDataSource() datasource.DataSource {
+	return &HealthDataSource{}
+}
+
+func (d *HealthDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_health"
+}
+
+type healthDataSourceModel struct {
+	Healthy   types.Bool   `tfsdk:"healthy"`
+	CheckedAt types.String `tfsdk:"checked_at"`
+	LatencyMs types.Int64  `tfsdk:"latency_ms"`
+}
+
+// Schema defines the schema for the data source.
+func (d *HealthDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Checks that the Zesty API is reachable and accepts the configured token. A failed check is reported as a warning and sets healthy to false, so it can be asserted with a check block or a postcondition.",
+		Attributes: map[string]schema.Attribute{
+			"healthy": schema.BoolAttribute{
+				Description: "Whether the Zesty API accepted the token",
+				Computed:    true,
+			},
+			"checked_at": schema.StringAttribute{
+				Description: "Time of the check, in RFC 3339 format",
+				Computed:    true,
+			},
+			"latency_ms": schema.Int64Attribute{
+				Description: "Round-trip time of the last request to the Zesty API, in milliseconds. Retries and the waits between them are not included",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *HealthDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	state := healthDataSourceModel{
+		Healthy:   types.BoolValue(true),
+		CheckedAt: types.StringValue(time.Now().UTC().Format(time.RFC3339)),
+	}
+
+	latency, err := d.client.Ping(ctx)
+	state.LatencyMs = types.Int64Value(latency.Milliseconds())
+	if err != nil {
+		tflog.Warn(ctx, "Zesty API health check failed", map[string]any{"error": err.Error()})
+		state.Healthy = types.BoolValue(false)
+		resp.Diagnostics.AddWarning(
+			"Zesty API Unhealthy",
+			fmt.Sprintf("The health check against the Zesty API at %s failed: %s", d.client.HostURL, err),
+		)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *HealthDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected: *providerData, got: %T.\nPlease report this issue to Zesty Support.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = data.client
+}
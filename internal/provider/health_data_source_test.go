@@ -0,0 +1,51 @@
+package provider_test
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccHealthDataSource(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_health" "api" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_health.api", "healthy", "true"),
+					resource.TestMatchResourceAttr("data.zesty_health.api", "checked_at", regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)),
+					resource.TestMatchResourceAttr("data.zesty_health.api", "latency_ms", regexp.MustCompile(`^\d+$`)),
+				),
+			},
+			{
+				// An unhealthy API is reported in the healthy attribute, not as an error.
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.validateStatus = http.StatusServiceUnavailable
+				},
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host            = %q
+  token           = "test-token"
+  skip_validation = true
+}
+
+data "zesty_health" "api" {}
+`, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_health.api", "healthy", "false"),
+					resource.TestCheckResourceAttrSet("data.zesty_health.api", "checked_at"),
+				),
+			},
+		},
+	})
+}
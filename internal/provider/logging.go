@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+// redacted replaces secrets in logged payloads and models.
+const redacted = "***"
+
+// loggablePayload returns a copy of payload that is safe to log, with the external ID redacted.
+func loggablePayload(payload models.Payload) models.Payload {
+	if payload.ExternalID != "" {
+		payload.ExternalID = redacted
+	}
+	return payload
+}
+
+// loggableAccount returns a copy of account that is safe to log, with the external ID redacted.
+// additional_data never holds the external ID, as withoutManagedKeys removes it.
+func loggableAccount(account accountModel) accountModel {
+	if account.ExternalID.ValueString() != "" {
+		account.ExternalID = types.StringValue(redacted)
+	}
+	return account
+}
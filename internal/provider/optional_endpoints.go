@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+)
+
+// Read-only data sources backed by optional endpoints, such as zesty_account_history, must keep
+// working against older backends that do not serve them. Such data sources expose the
+// availableAttribute and use endpointAvailable to translate a 404 on their endpoint into
+// `available = false` with the remaining attributes left empty, instead of failing the read.
+
+func availableAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: "Whether the Zesty API serves this feature. False when the backend does not support it yet, in which case all other attributes are empty.",
+		Computed:    true,
+	}
+}
+
+// endpointAvailable reports whether an optional endpoint answered the request.
+// A 404 is treated as the feature being unavailable; any other error is added to diags.
+func endpointAvailable(err error, summary string, diags *diag.Diagnostics) bool {
+	if err == nil {
+		return true
+	}
+
+	if !client.IsNotFound(err) {
+		diags.AddError(summary, err.Error())
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+)
+
+// ProductsDataSource lists the products known to the provider. The API has no endpoint listing
+// products, so the list comes from models.Products.
+type ProductsDataSource struct{}
+
+var _ datasource.DataSource = &ProductsDataSource{}
+
+func NewProductsDataSource() datasource.DataSource {
+	return &ProductsDataSource{}
+}
+
+func (d *ProductsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_products"
+}
+
+type productsDataSourceModel struct {
+	Products []productTypeModel `tfsdk:"products"`
+}
+
+type productTypeModel struct {
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+}
+
+// Schema defines the schema for the data source.
+func (d *ProductsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the products that can be activated on an account.",
+		Attributes: map[string]schema.Attribute{
+			"products": schema.ListNestedAttribute{
+				Description: "List of products.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of product, as used in the account's products list (e.g. Kompass)",
+							Computed:    true,
+						},
+						"description": schema.StringAttribute{
+							Description: "Short description of the product",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ProductsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	state := productsDataSourceModel{
+		Products: []productTypeModel{},
+	}
+	for _, product := range models.Products {
+		state.Products = append(state.Products, productTypeModel{
+			Name:        types.StringValue(string(product)),
+			Description: types.StringValue(models.ProductDescriptions[product]),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,29 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccProductsDataSource(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_products" "all" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zesty_products.all", "products.#", "3"),
+					resource.TestCheckResourceAttr("data.zesty_products.all", "products.0.name", "Kompass"),
+					resource.TestCheckResourceAttr("data.zesty_products.all", "products.1.name", "CM"),
+					resource.TestCheckResourceAttr("data.zesty_products.all", "products.2.name", "ZestyDisk"),
+					resource.TestCheckResourceAttrSet("data.zesty_products.all", "products.0.description"),
+				),
+			},
+		},
+	})
+}
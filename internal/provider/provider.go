@@ -3,9 +3,16 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -14,6 +21,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/zesty-co/terraform-provider-zesty/internal/client"
 	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+	"golang.org/x/time/rate"
 )
 
 type ZestyProvider struct {
@@ -21,13 +29,70 @@ type ZestyProvider struct {
 }
 
 type ZestyProviderModel struct {
-	Host  types.String `tfsdk:"host"`
-	Token types.String `tfsdk:"token"`
+	Host                 types.String            `tfsdk:"host"`
+	BasePath             types.String            `tfsdk:"base_path"`
+	AccountIDParam       types.String            `tfsdk:"account_id_param"`
+	Token                types.String            `tfsdk:"token"`
+	TokenFile            types.String            `tfsdk:"token_file"`
+	SkipValidation       types.Bool              `tfsdk:"skip_validation"`
+	RequestTimeout       types.Int64             `tfsdk:"request_timeout"`
+	CACertFile           types.String            `tfsdk:"ca_cert_file"`
+	InsecureSkipVerify   types.Bool              `tfsdk:"insecure_skip_verify"`
+	ProxyURL             types.String            `tfsdk:"proxy_url"`
+	MaxIdleConns         types.Int64             `tfsdk:"max_idle_conns"`
+	MaxIdleConnsPerHost  types.Int64             `tfsdk:"max_idle_conns_per_host"`
+	IdleConnTimeout      types.Int64             `tfsdk:"idle_conn_timeout"`
+	MaxTotalRetries      types.Int64             `tfsdk:"max_total_retries"`
+	ValuesFormat         types.String            `tfsdk:"values_format"`
+	RequestsPerSecond    types.Float64           `tfsdk:"requests_per_second"`
+	Profile              types.String            `tfsdk:"profile"`
+	CredentialsFile      types.String            `tfsdk:"credentials_file"`
+	VaultTokenPath       types.String            `tfsdk:"vault_token_path"`
+	DefaultCloudProvider types.String            `tfsdk:"default_cloud_provider"`
+	ReadOnly             types.Bool              `tfsdk:"read_only"`
+	AllowEmptyProducts   types.Bool              `tfsdk:"allow_empty_products"`
+	ExtraHeaders         map[string]types.String `tfsdk:"extra_headers"`
+	Tenants              map[string]types.String `tfsdk:"tenants"`
+	LogLevel             types.String            `tfsdk:"log_level"`
+	StrictDecoding       types.Bool              `tfsdk:"strict_decoding"`
+	OmitProductValues    types.Bool              `tfsdk:"omit_product_values"`
+	ReadyStatuses        []types.String          `tfsdk:"ready_onboarding_statuses"`
+	Retry                *retryModel             `tfsdk:"retry"`
+}
+
+// retryModel is the retry block of the provider, configuring how failed requests are retried.
+type retryModel struct {
+	MaxAttempts   types.Int64   `tfsdk:"max_attempts"`
+	WaitMin       types.String  `tfsdk:"wait_min"`
+	WaitMax       types.String  `tfsdk:"wait_max"`
+	RetryOnStatus []types.Int64 `tfsdk:"retry_on_status"`
+}
+
+// providerData is handed to data sources and resources through their Configure methods.
+type providerData struct {
+	client       *client.Client
+	valuesFormat string
+	// defaultCloudProvider is used for accounts that omit cloud_provider. It is empty when not
+	// configured.
+	defaultCloudProvider models.CloudProvider
+	// allowEmptyProducts lets accounts be planned with an empty products list.
+	allowEmptyProducts bool
+	// omitProductValues leaves the values of products read by data sources out of state, keeping
+	// only their checksum.
+	omitProductValues bool
+	// readyStatuses are the onboarding statuses of fully onboarded accounts, which set their ready
+	// attribute.
+	readyStatuses []models.OnboardingStatus
+	// tenants maps the names of other tenants to their tokens, for accounts selecting one with their
+	// tenant attribute.
+	tenants map[string]string
 }
 
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
-		return &ZestyProvider{}
+		return &ZestyProvider{
+			version: version,
+		}
 	}
 }
 
@@ -42,7 +107,15 @@ func (p *ZestyProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				Description: "URI for Zesty API. May also be provided by the ZESTY_HOST environment variable.",
+				Description: "URI for Zesty API, as an absolute http or https URL. May also be provided by the ZESTY_HOST environment variable.",
+				Optional:    true,
+			},
+			"base_path": schema.StringAttribute{
+				Description: "Path prefix the Zesty API is served under, such as /zesty/v2 behind a gateway. It is prepended to the path of every endpoint, with leading, trailing and repeated slashes normalized. May also be provided by the ZESTY_BASE_PATH environment variable.",
+				Optional:    true,
+			},
+			"account_id_param": schema.StringAttribute{
+				Description: "Name of the query parameter carrying the account ID of account lookups, such as account_id for a gateway with its own naming conventions. May also be provided by the ZESTY_ACCOUNT_ID_PARAM environment variable. Defaults to accountID.",
 				Optional:    true,
 			},
 			"token": schema.StringAttribute{
@@ -50,10 +123,138 @@ func (p *ZestyProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"token_file": schema.StringAttribute{
+				Description: "Path to a file holding the token for Zesty API, such as a secret mounted by a CI system. Surrounding whitespace is trimmed. An explicit token attribute takes precedence over the file, which takes precedence over Vault, the profile and environment variables. May also be provided by the ZESTY_API_TOKEN_FILE environment variable.",
+				Optional:    true,
+			},
+			"profile": schema.StringAttribute{
+				Description: "Name of a profile in the shared credentials file to read host and token from. Explicit host and token attributes take precedence over the profile, which takes precedence over environment variables. May also be provided by the ZESTY_PROFILE environment variable.",
+				Optional:    true,
+			},
+			"credentials_file": schema.StringAttribute{
+				Description: "Path to the shared credentials file, in INI or JSON format. Only read when a profile is set. May also be provided by the ZESTY_CREDENTIALS_FILE environment variable. Defaults to ~/.zesty/credentials.",
+				Optional:    true,
+			},
+			"vault_token_path": schema.StringAttribute{
+				Description: "Path of a HashiCorp Vault secret to read the token, and optionally the host, from, such as secret/data/zesty. The secret must have a token field and may have a host field. Vault is reached through the VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE environment variables, with the ca_cert_file, insecure_skip_verify and proxy_url settings of the Zesty API. Explicit host and token attributes take precedence over Vault, which takes precedence over the profile and environment variables. May also be provided by the ZESTY_VAULT_TOKEN_PATH environment variable.",
+				Optional:    true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "Path to a PEM file of certificate authorities to trust when connecting to the Zesty API, in addition to the system pool.",
+				Optional:    true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Description: "Skip verification of the Zesty API TLS certificate. Only use this for testing. Defaults to false.",
+				Optional:    true,
+			},
+			"proxy_url": schema.StringAttribute{
+				Description: "URL of an http, https or socks5 proxy for requests to the Zesty API. Overrides the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, which are honored otherwise.",
+				Optional:    true,
+			},
+			"max_idle_conns": schema.Int64Attribute{
+				Description: "Maximum number of idle keep-alive connections to the Zesty API kept open for reuse. Defaults to 100.",
+				Optional:    true,
+			},
+			"max_idle_conns_per_host": schema.Int64Attribute{
+				Description: "Maximum number of idle keep-alive connections kept open per host. Defaults to 10.",
+				Optional:    true,
+			},
+			"idle_conn_timeout": schema.Int64Attribute{
+				Description: "Time in seconds an idle keep-alive connection is kept open before being closed. Defaults to 90.",
+				Optional:    true,
+			},
+			"max_total_retries": schema.Int64Attribute{
+				Description: "Maximum number of retries of failed requests to the Zesty API, shared by all resources and data sources for the whole run. Once spent, failures are reported without retrying, which keeps many failing resources from flooding a struggling API. Set to 0 to disable retries. Unlimited by default.",
+				Optional:    true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Description: "Maximum number of requests per second sent to the Zesty API. May also be provided by the ZESTY_REQUESTS_PER_SECOND environment variable. Unlimited by default.",
+				Optional:    true,
+			},
+			"request_timeout": schema.Int64Attribute{
+				Description: "Timeout in seconds for requests to the Zesty API. May also be provided by the ZESTY_REQUEST_TIMEOUT environment variable. Defaults to 180.",
+				Optional:    true,
+			},
+			"values_format": schema.StringAttribute{
+				Description: "Encoding of product values read from the Zesty API, either yaml or json. May also be provided by the ZESTY_VALUES_FORMAT environment variable. Defaults to yaml.",
+				Optional:    true,
+			},
+			"default_cloud_provider": schema.StringAttribute{
+				Description: "Cloud provider of zesty_account resources that omit cloud_provider. One of AWS, Azure, GCP or OCI. May also be provided by the ZESTY_DEFAULT_CLOUD_PROVIDER environment variable.",
+				Optional:    true,
+			},
+			"skip_validation": schema.BoolAttribute{
+				Description: "Skip validating the token against the Zesty API when configuring the provider. May also be provided by the ZESTY_SKIP_VALIDATION environment variable. Defaults to false.",
+				Optional:    true,
+			},
+			"extra_headers": schema.MapAttribute{
+				Description: "Headers sent with every request to the Zesty API, for example a tenant header required by a gateway. Headers set by the provider itself, such as X-Api-Key, X-API-Version, Accept, Content-Type and User-Agent, cannot be overridden. Sensitive, as headers may carry credentials.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"tenants": schema.MapAttribute{
+				Description: "Tokens for Zesty API of other tenants, by a name of your choosing, for managing accounts of several tenants in one configuration. Accounts select a tenant by name with their tenant attribute, so the tokens never land in state. A provider alias per tenant is an alternative.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "Never create, update or delete accounts through the Zesty API. Mutating operations only echo the planned values into state, which is useful for experimenting with a real token. Accounts the Zesty API does not know are kept in state as they are when refreshed, as they may only have been created in state. May also be provided by the ZESTY_READ_ONLY environment variable. Defaults to false.",
+				Optional:    true,
+			},
+			"allow_empty_products": schema.BoolAttribute{
+				Description: "Allow accounts with an empty products list, which are otherwise rejected as no product would be activated on them. May also be provided by the ZESTY_ALLOW_EMPTY_PRODUCTS environment variable. Defaults to false.",
+				Optional:    true,
+			},
+			"log_level": schema.StringAttribute{
+				Description: "Level of the logs of requests to and responses from the Zesty API, one of trace, debug, info, warn or error. Raises the verbosity of this provider without setting TF_LOG for every provider. May also be provided by the ZESTY_LOG_LEVEL environment variable. Defaults to the level of the provider logger.",
+				Optional:    true,
+			},
+			"strict_decoding": schema.BoolAttribute{
+				Description: "Warn about fields of Zesty API responses the provider does not know, to debug fields renamed on the API side. Responses are still decoded as usual. May also be provided by the ZESTY_STRICT_DECODING environment variable. Defaults to false.",
+				Optional:    true,
+			},
+			"omit_product_values": schema.BoolAttribute{
+				Description: "Leave the values and values_map of products read by the zesty_accounts and zesty_account_history data sources out of state, keeping only their values_hash, to reduce state size and plan noise for large values. The values of zesty_account resources are kept, as Read compares them with the API to detect drift and Update diffs them to send only changes. They cannot be write-only either, as products is a set, which cannot hold write-only attributes. May also be provided by the ZESTY_OMIT_PRODUCT_VALUES environment variable. Defaults to false.",
+				Optional:    true,
+			},
+			"ready_onboarding_statuses": schema.ListAttribute{
+				Description: "Onboarding statuses of fully onboarded accounts, for which the ready attribute of accounts is true. Defaults to [\"Completed\"].",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": schema.SingleNestedBlock{
+				Description: "Retries of failed requests to the Zesty API. Requests are not retried when the block is omitted.",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						Description: "Number of times a request is sent before its failure is reported, including the first attempt. Required when the block is set.",
+						Optional:    true,
+					},
+					"wait_min": schema.StringAttribute{
+						Description: "Wait before the first retry, as a duration such as 500ms or 2s. Doubled after each retry up to wait_max, and jittered down to half its length. Defaults to 1s.",
+						Optional:    true,
+					},
+					"wait_max": schema.StringAttribute{
+						Description: "Longest wait between two retries, as a duration such as 30s. Defaults to 30s, or wait_min when that is longer.",
+						Optional:    true,
+					},
+					"retry_on_status": schema.ListAttribute{
+						Description: "Status codes of the responses that are retried, such as [409, 503]. Defaults to 429 and any 5xx. Network errors and timeouts are retried either way.",
+						ElementType: types.Int64Type,
+						Optional:    true,
+					},
+				},
+			},
 		},
 	}
 }
 
+// logLevels lists the levels accepted by the log_level attribute.
+var logLevels = []hclog.Level{hclog.Trace, hclog.Debug, hclog.Info, hclog.Warn, hclog.Error}
+
 // Configure prepares a Zesty API client for data sources and resources.
 func (p *ZestyProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	tflog.Info(ctx, "Configuring Zesty API client")
@@ -87,18 +288,381 @@ func (p *ZestyProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	host := os.Getenv("ZESTY_HOST")
 	token := os.Getenv("ZESTY_API_TOKEN")
 
+	skipValidation := false
+	if value := os.Getenv("ZESTY_SKIP_VALIDATION"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("skip_validation"),
+				"Invalid ZESTY_SKIP_VALIDATION Value",
+				fmt.Sprintf("The ZESTY_SKIP_VALIDATION environment variable must be a boolean, got %q.", value),
+			)
+			return
+		}
+		skipValidation = parsed
+	}
+
+	readOnly := false
+	if value := os.Getenv("ZESTY_READ_ONLY"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("read_only"),
+				"Invalid ZESTY_READ_ONLY Value",
+				fmt.Sprintf("The ZESTY_READ_ONLY environment variable must be a boolean, got %q.", value),
+			)
+			return
+		}
+		readOnly = parsed
+	}
+
+	strictDecoding := false
+	if value := os.Getenv("ZESTY_STRICT_DECODING"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("strict_decoding"),
+				"Invalid ZESTY_STRICT_DECODING Value",
+				fmt.Sprintf("The ZESTY_STRICT_DECODING environment variable must be a boolean, got %q.", value),
+			)
+			return
+		}
+		strictDecoding = parsed
+	}
+
+	allowEmptyProducts := false
+	if value := os.Getenv("ZESTY_ALLOW_EMPTY_PRODUCTS"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("allow_empty_products"),
+				"Invalid ZESTY_ALLOW_EMPTY_PRODUCTS Value",
+				fmt.Sprintf("The ZESTY_ALLOW_EMPTY_PRODUCTS environment variable must be a boolean, got %q.", value),
+			)
+			return
+		}
+		allowEmptyProducts = parsed
+	}
+
+	omitProductValues := false
+	if value := os.Getenv("ZESTY_OMIT_PRODUCT_VALUES"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("omit_product_values"),
+				"Invalid ZESTY_OMIT_PRODUCT_VALUES Value",
+				fmt.Sprintf("The ZESTY_OMIT_PRODUCT_VALUES environment variable must be a boolean, got %q.", value),
+			)
+			return
+		}
+		omitProductValues = parsed
+	}
+
+	profile := os.Getenv("ZESTY_PROFILE")
+	if !config.Profile.IsNull() {
+		profile = config.Profile.ValueString()
+	}
+
+	if profile != "" {
+		credentialsFile := os.Getenv("ZESTY_CREDENTIALS_FILE")
+		if !config.CredentialsFile.IsNull() {
+			credentialsFile = config.CredentialsFile.ValueString()
+		}
+
+		credentials, err := readCredentialsProfile(credentialsFile, profile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("profile"),
+				"Unable to Read Zesty Credentials Profile",
+				fmt.Sprintf("Could not read profile %q from the shared credentials file: %s", profile, err),
+			)
+			return
+		}
+
+		if credentials.Host != "" {
+			host = credentials.Host
+		}
+		if credentials.Token != "" {
+			token = credentials.Token
+		}
+	}
+
+	transport, err := client.NewTransport(client.TransportConfig{
+		CACertFile:          config.CACertFile.ValueString(),
+		InsecureSkipVerify:  config.InsecureSkipVerify.ValueBool(),
+		ProxyURL:            config.ProxyURL.ValueString(),
+		MaxIdleConns:        int(config.MaxIdleConns.ValueInt64()),
+		MaxIdleConnsPerHost: int(config.MaxIdleConnsPerHost.ValueInt64()),
+		IdleConnTimeout:     time.Duration(config.IdleConnTimeout.ValueInt64()) * time.Second,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Configure Zesty API Transport",
+			fmt.Sprintf("An unexpected error occurred when configuring the Zesty API client transport. Error: %s", err),
+		)
+		return
+	}
+
+	vaultTokenPath := os.Getenv("ZESTY_VAULT_TOKEN_PATH")
+	if !config.VaultTokenPath.IsNull() {
+		vaultTokenPath = config.VaultTokenPath.ValueString()
+	}
+
+	if vaultTokenPath != "" {
+		// Vault is reached through the transport of the Zesty API, so it honors the same CA
+		// certificates, TLS settings and proxy.
+		credentials, err := readVaultCredentials(ctx, &http.Client{Transport: transport}, vaultTokenPath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("vault_token_path"),
+				"Unable to Read Zesty Token from Vault",
+				fmt.Sprintf("Could not read the Zesty token from Vault secret %q: %s", vaultTokenPath, err),
+			)
+			return
+		}
+
+		if credentials.Host != "" {
+			host = credentials.Host
+		}
+		token = credentials.Token
+	}
+
+	tokenFile := os.Getenv("ZESTY_API_TOKEN_FILE")
+	if !config.TokenFile.IsNull() {
+		tokenFile = config.TokenFile.ValueString()
+	}
+
+	if tokenFile != "" {
+		fileToken, err := readTokenFile(tokenFile)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token_file"),
+				"Unable to Read Zesty Token File",
+				fmt.Sprintf("Could not read the Zesty token from %q: %s", tokenFile, err),
+			)
+			return
+		}
+
+		token = fileToken
+	}
+
 	if !config.Host.IsNull() {
 		host = config.Host.ValueString()
 	}
 
+	basePath := os.Getenv("ZESTY_BASE_PATH")
+	if !config.BasePath.IsNull() {
+		basePath = config.BasePath.ValueString()
+	}
+
+	accountIDParam := os.Getenv("ZESTY_ACCOUNT_ID_PARAM")
+	if !config.AccountIDParam.IsNull() {
+		accountIDParam = config.AccountIDParam.ValueString()
+	}
+
 	if !config.Token.IsNull() {
 		token = config.Token.ValueString()
 	}
 
+	if !config.SkipValidation.IsNull() {
+		skipValidation = config.SkipValidation.ValueBool()
+	}
+
+	if !config.ReadOnly.IsNull() {
+		readOnly = config.ReadOnly.ValueBool()
+	}
+
+	if !config.StrictDecoding.IsNull() {
+		strictDecoding = config.StrictDecoding.ValueBool()
+	}
+
+	if !config.AllowEmptyProducts.IsNull() {
+		allowEmptyProducts = config.AllowEmptyProducts.ValueBool()
+	}
+
+	if !config.OmitProductValues.IsNull() {
+		omitProductValues = config.OmitProductValues.ValueBool()
+	}
+
+	readyStatuses := models.ReadyOnboardingStatuses
+	if config.ReadyStatuses != nil {
+		readyStatuses = make([]models.OnboardingStatus, len(config.ReadyStatuses))
+		for i, status := range config.ReadyStatuses {
+			readyStatuses[i] = models.OnboardingStatus(status.ValueString())
+		}
+	}
+
+	requestTimeout := int64(client.DefaultTimeout / time.Second)
+	if value := os.Getenv("ZESTY_REQUEST_TIMEOUT"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid ZESTY_REQUEST_TIMEOUT Value",
+				fmt.Sprintf("The ZESTY_REQUEST_TIMEOUT environment variable must be a number of seconds, got %q.", value),
+			)
+			return
+		}
+		requestTimeout = parsed
+	}
+
+	if !config.RequestTimeout.IsNull() {
+		requestTimeout = config.RequestTimeout.ValueInt64()
+	}
+
+	if requestTimeout <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("request_timeout"),
+			"Invalid Zesty API Request Timeout",
+			fmt.Sprintf("The request timeout must be a positive number of seconds, got %d.", requestTimeout),
+		)
+	}
+
+	limit := rate.Inf
+	if value := os.Getenv("ZESTY_REQUESTS_PER_SECOND"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("requests_per_second"),
+				"Invalid ZESTY_REQUESTS_PER_SECOND Value",
+				fmt.Sprintf("The ZESTY_REQUESTS_PER_SECOND environment variable must be a number, got %q.", value),
+			)
+			return
+		}
+		limit = rate.Limit(parsed)
+	}
+
+	if !config.RequestsPerSecond.IsNull() {
+		limit = rate.Limit(config.RequestsPerSecond.ValueFloat64())
+	}
+
+	if limit <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Invalid Zesty API Request Rate",
+			fmt.Sprintf("The request rate must be a positive number of requests per second, got %v.", float64(limit)),
+		)
+	}
+
+	for name, value := range map[string]types.Int64{
+		"max_idle_conns":          config.MaxIdleConns,
+		"max_idle_conns_per_host": config.MaxIdleConnsPerHost,
+		"idle_conn_timeout":       config.IdleConnTimeout,
+	} {
+		if !value.IsNull() && value.ValueInt64() <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid Zesty API Connection Pool Setting",
+				fmt.Sprintf("%s must be a positive number, got %d.", name, value.ValueInt64()),
+			)
+		}
+	}
+
+	if !config.MaxTotalRetries.IsNull() && config.MaxTotalRetries.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_total_retries"),
+			"Invalid Zesty API Retry Budget",
+			fmt.Sprintf("max_total_retries must not be negative, got %d.", config.MaxTotalRetries.ValueInt64()),
+		)
+	}
+
+	var retryOptions []client.Option
+	if config.Retry != nil {
+		retry, diags := retryConfig(config.Retry)
+		resp.Diagnostics.Append(diags...)
+		retryOptions = append(retryOptions, client.WithRetryConfig(retry))
+	}
+
+	extraHeaders := map[string]string{}
+	for name, value := range config.ExtraHeaders {
+		if client.IsReservedHeader(name) {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("extra_headers").AtMapKey(name),
+				"Reserved Header Ignored",
+				fmt.Sprintf("The %s header is set by the provider and cannot be overridden with extra_headers. Reserved headers are: %s.", name, strings.Join(client.ReservedHeaders, ", ")),
+			)
+			continue
+		}
+		extraHeaders[name] = value.ValueString()
+	}
+
+	tenants := map[string]string{}
+	for name, value := range config.Tenants {
+		if value.IsNull() || value.ValueString() == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("tenants").AtMapKey(name),
+				"Missing Zesty Tenant Token",
+				fmt.Sprintf("The token of tenant %q must not be empty.", name),
+			)
+			continue
+		}
+		tenants[name] = value.ValueString()
+	}
+
+	valuesFormat := os.Getenv("ZESTY_VALUES_FORMAT")
+	if !config.ValuesFormat.IsNull() {
+		valuesFormat = config.ValuesFormat.ValueString()
+	}
+	if valuesFormat == "" {
+		valuesFormat = ValuesFormatYAML
+	}
+
+	if valuesFormat != ValuesFormatYAML && valuesFormat != ValuesFormatJSON {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("values_format"),
+			"Invalid Product Values Format",
+			fmt.Sprintf("The values format must be either %q or %q, got %q.", ValuesFormatYAML, ValuesFormatJSON, valuesFormat),
+		)
+	}
+
+	defaultCloudProviderName := os.Getenv("ZESTY_DEFAULT_CLOUD_PROVIDER")
+	if !config.DefaultCloudProvider.IsNull() {
+		defaultCloudProviderName = config.DefaultCloudProvider.ValueString()
+	}
+
+	var defaultCloudProvider models.CloudProvider
+	if defaultCloudProviderName != "" {
+		parsed, err := models.ParseCloudProvider(defaultCloudProviderName)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("default_cloud_provider"),
+				"Invalid Default Cloud Provider",
+				fmt.Sprintf("The default cloud provider is invalid: %s.", err),
+			)
+		}
+		defaultCloudProvider = parsed
+	}
+
+	logLevelName := os.Getenv("ZESTY_LOG_LEVEL")
+	if !config.LogLevel.IsNull() {
+		logLevelName = config.LogLevel.ValueString()
+	}
+
+	logLevel := hclog.NoLevel
+	if logLevelName != "" {
+		logLevel = hclog.LevelFromString(logLevelName)
+		if !slices.Contains(logLevels, logLevel) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("log_level"),
+				"Invalid Log Level",
+				fmt.Sprintf("The log level must be one of trace, debug, info, warn or error, got %q.", logLevelName),
+			)
+		}
+	}
+
 	if host == "" {
 		host = models.DefaultHostURL
 	}
 
+	host, err = client.NormalizeHostURL(host)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("host"),
+			"Invalid Zesty API Host",
+			fmt.Sprintf("The Zesty API host must be an absolute http or https URL such as %s. Error: %s", models.DefaultHostURL, err),
+		)
+	}
+
 	if token == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
@@ -116,7 +680,28 @@ func (p *ZestyProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "zesty_api_token")
 	tflog.Debug(ctx, "Creating Zesty API client")
 
-	client, err := client.NewClient(&host, token)
+	opts := []client.Option{
+		client.WithHost(host),
+		client.WithBasePath(basePath),
+		client.WithAuthHeader(token),
+		client.WithHTTPClient(&http.Client{Transport: transport}),
+		client.WithTimeout(time.Duration(requestTimeout) * time.Second),
+		client.WithUserAgent(fmt.Sprintf("%s/%s", client.UserAgentPrefix, p.version)),
+		client.WithRateLimit(limit),
+		client.WithReadOnly(readOnly),
+		client.WithExtraHeaders(extraHeaders),
+		client.WithLogLevel(logLevel),
+		client.WithStrictDecoding(strictDecoding),
+	}
+	if accountIDParam != "" {
+		opts = append(opts, client.WithAccountIDParam(accountIDParam))
+	}
+	if !config.MaxTotalRetries.IsNull() {
+		opts = append(opts, client.WithRetryBudget(config.MaxTotalRetries.ValueInt64()))
+	}
+	opts = append(opts, retryOptions...)
+
+	apiClient, err := client.NewClientWithOptions(opts...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Zesty API Client",
@@ -125,25 +710,120 @@ func (p *ZestyProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	err = client.Validate()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Validate Zesty API Client",
-			fmt.Sprintf("An unexpected error occurred when validating the Zesty API. Error: %s", err),
-		)
-		return
+	if skipValidation {
+		tflog.Debug(ctx, "Skipping Zesty API client validation")
+	} else {
+		err = apiClient.Validate(ctx)
+		switch {
+		case err == nil:
+		case client.IsUnauthorized(err):
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token"),
+				"Invalid Zesty API Token",
+				fmt.Sprintf("The Zesty API rejected the token. Check that the token is correct and has not been revoked. Error: %s", err),
+			)
+			return
+		case client.IsVersionMismatch(err):
+			resp.Diagnostics.AddAttributeError(
+				path.Root("host"),
+				"Incompatible Zesty API Version",
+				fmt.Sprintf("The Zesty API at %s does not match the version this provider supports. Error: %s", host, err),
+			)
+			return
+		case client.IsTemporary(err):
+			resp.Diagnostics.AddError(
+				"Zesty API Temporarily Unavailable",
+				fmt.Sprintf("The Zesty API could not be reached at %s. This is usually temporary, so try again shortly. Error: %s", host, err),
+			)
+			return
+		default:
+			resp.Diagnostics.AddError(
+				"Unable to Validate Zesty API Client",
+				fmt.Sprintf("An unexpected error occurred when validating the Zesty API. Error: %s", err),
+			)
+			return
+		}
 	}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	data := &providerData{
+		client:               apiClient,
+		valuesFormat:         valuesFormat,
+		defaultCloudProvider: defaultCloudProvider,
+		allowEmptyProducts:   allowEmptyProducts,
+		omitProductValues:    omitProductValues,
+		readyStatuses:        readyStatuses,
+		tenants:              tenants,
+	}
+	resp.DataSourceData = data
+	resp.ResourceData = data
 
 	tflog.Info(ctx, "Configured Zesty API client", map[string]any{"success": true})
 }
 
+// retryConfig converts the retry block into the client retry configuration, reporting invalid
+// attributes. Unset waits are left for the client to default.
+func retryConfig(retry *retryModel) (client.RetryConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var config client.RetryConfig
+
+	if retry.MaxAttempts.IsNull() || retry.MaxAttempts.ValueInt64() < 1 {
+		diags.AddAttributeError(
+			path.Root("retry").AtName("max_attempts"),
+			"Invalid Zesty API Retry Setting",
+			"max_attempts must be set to a number of at least 1 in the retry block.",
+		)
+	} else {
+		config.MaxAttempts = int(retry.MaxAttempts.ValueInt64())
+	}
+
+	parseWait := func(name string, value types.String) time.Duration {
+		if value.IsNull() {
+			return 0
+		}
+		wait, err := time.ParseDuration(value.ValueString())
+		if err != nil || wait < 0 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName(name),
+				"Invalid Zesty API Retry Setting",
+				fmt.Sprintf("%s must be a non-negative duration such as 500ms or 2s, got %q.", name, value.ValueString()),
+			)
+			return 0
+		}
+		return wait
+	}
+	config.WaitMin = parseWait("wait_min", retry.WaitMin)
+	config.WaitMax = parseWait("wait_max", retry.WaitMax)
+	if config.WaitMin > 0 && config.WaitMax > 0 && config.WaitMax < config.WaitMin {
+		diags.AddAttributeError(
+			path.Root("retry").AtName("wait_max"),
+			"Invalid Zesty API Retry Setting",
+			fmt.Sprintf("wait_max %s must not be less than wait_min %s.", config.WaitMax, config.WaitMin),
+		)
+	}
+
+	for i, status := range retry.RetryOnStatus {
+		code := status.ValueInt64()
+		if status.IsNull() || code < 100 || code > 599 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName("retry_on_status").AtListIndex(i),
+				"Invalid Zesty API Retry Setting",
+				fmt.Sprintf("retry_on_status must only hold HTTP status codes between 100 and 599, got %s.", status),
+			)
+			continue
+		}
+		config.RetryOnStatus = append(config.RetryOnStatus, int(code))
+	}
+
+	return config, diags
+}
+
 // DataSources defines the data sources implemented in the provider.
 func (p *ZestyProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewAccountsDataSource,
+		NewProductsDataSource,
+		NewHealthDataSource,
+		NewAccountHistoryDataSource,
 	}
 }
 
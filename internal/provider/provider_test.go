@@ -0,0 +1,1384 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	frameworkprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zesty-co/terraform-provider-zesty/internal/client"
+	"github.com/zesty-co/terraform-provider-zesty/internal/models"
+	"github.com/zesty-co/terraform-provider-zesty/internal/provider"
+)
+
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"zesty": providerserver.NewProtocol6WithError(provider.New("test")()),
+}
+
+// testAPI is an in-memory stand-in for the Zesty API used by acceptance tests.
+type testAPI struct {
+	mu             sync.Mutex
+	accounts       map[string]models.Account
+	validateCalls  int
+	validateStatus int
+	validateDelay  time.Duration
+	updatedAt      time.Time
+	// createDelay holds POST /account requests back, like a slow onboarding, until it passes or
+	// the client gives up.
+	createDelay time.Duration
+	// omitInactive drops inactive products from stored accounts, like API versions that only
+	// report active products.
+	omitInactive bool
+	lastPayload  models.Payload
+	// deleteStatus, when set, is returned for DELETE requests instead of deleting the account.
+	deleteStatus int
+	// deletionModes holds the mode query parameter of each DELETE request.
+	deletionModes []string
+	// deleteLingers, when set, keeps deleted accounts readable for that many GET /account requests,
+	// like a soft delete processed in the background. lingering counts down the remaining reads.
+	deleteLingers int
+	lingering     map[string]int
+	// mutations counts POST, PUT, PATCH and DELETE requests.
+	mutations int
+	// lastPatch holds the body of the last PATCH request.
+	lastPatch map[string]any
+	// lastHeader holds the headers of the last request.
+	lastHeader http.Header
+	// tokens holds the token of the last request of each method.
+	tokens map[string]string
+	// history holds the snapshots served for each account by the history endpoint.
+	history map[string][]models.AccountSnapshot
+	// historyStatus, when set, is returned by the history endpoint instead of the snapshots.
+	historyStatus int
+	// apiVersion, when set, is reported in the X-API-Version header of every response.
+	apiVersion string
+}
+
+func newTestAPI(t *testing.T) (*testAPI, *httptest.Server) {
+	api := &testAPI{
+		accounts:       map[string]models.Account{},
+		lingering:      map[string]int{},
+		tokens:         map[string]string{},
+		history:        map[string][]models.AccountSnapshot{},
+		validateStatus: http.StatusOK,
+	}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func (a *testAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.lastHeader = r.Header.Clone()
+	a.tokens[r.Method] = r.Header.Get("X-Api-Key")
+	if a.apiVersion != "" {
+		w.Header().Set(client.APIVersionHeader, a.apiVersion)
+	}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch || r.Method == http.MethodDelete {
+		a.mutations++
+	}
+
+	switch {
+	case r.URL.Path == "/validate":
+		a.validateCalls++
+		time.Sleep(a.validateDelay)
+		w.WriteHeader(a.validateStatus)
+	case r.URL.Path == "/accounts" && r.Method == http.MethodGet:
+		query := r.URL.Query()
+		accounts := []models.Account{}
+		for _, account := range a.accounts {
+			if cloudProvider := query.Get("cloudProvider"); cloudProvider != "" && string(account.CloudProvider) != cloudProvider {
+				continue
+			}
+			if orgID := query.Get("organizationID"); orgID != "" && strconv.FormatInt(account.OrganizationID, 10) != orgID {
+				continue
+			}
+			if product := query.Get("product"); product != "" {
+				if _, ok := account.Products[models.Product(product)]; !ok {
+					continue
+				}
+			}
+			accounts = append(accounts, account)
+		}
+		writeJSON(w, http.StatusOK, accounts)
+	case r.URL.Path == "/"+models.APIVersion+"/account/history" && r.Method == http.MethodGet:
+		if a.historyStatus != 0 {
+			w.WriteHeader(a.historyStatus)
+			return
+		}
+		snapshots, ok := a.history[r.URL.Query().Get("accountID")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, snapshots)
+	case r.URL.Path == "/account" && r.Method == http.MethodGet:
+		if externalID := r.URL.Query().Get(client.ExternalIDParam); externalID != "" {
+			for _, account := range a.accounts {
+				if account.AdditionalData["externalID"] == externalID {
+					writeJSON(w, http.StatusOK, account)
+					return
+				}
+			}
+			http.NotFound(w, r)
+			return
+		}
+		if reads, ok := a.lingering[r.URL.Query().Get("accountID")]; ok {
+			if reads == 0 {
+				delete(a.accounts, r.URL.Query().Get("accountID"))
+			}
+			a.lingering[r.URL.Query().Get("accountID")] = reads - 1
+		}
+		account, ok := a.accounts[r.URL.Query().Get("accountID")]
+		if orgID := r.URL.Query().Get("organizationID"); orgID != "" && strconv.FormatInt(account.OrganizationID, 10) != orgID {
+			ok = false
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, account)
+	case r.URL.Path == "/account" && (r.Method == http.MethodPost || r.Method == http.MethodPut):
+		if r.Method == http.MethodPost && a.createDelay > 0 {
+			select {
+			case <-time.After(a.createDelay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		var payload models.Payload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		account := a.store(payload)
+		status := http.StatusOK
+		if r.Method == http.MethodPost {
+			status = http.StatusCreated
+		}
+		writeJSON(w, status, account)
+	case r.URL.Path == "/account" && r.Method == http.MethodPatch:
+		var patch map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		a.lastPatch = patch
+		accountID, _ := patch["accountID"].(string)
+		account, ok := a.accounts[accountID]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		payload, err := applyMergePatch(payloadFromAccount(account), patch)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, a.store(payload))
+	case r.URL.Path == "/account" && r.Method == http.MethodDelete:
+		a.deletionModes = append(a.deletionModes, r.URL.Query().Get(client.DeletionModeParam))
+		if a.deleteStatus != 0 {
+			http.Error(w, http.StatusText(a.deleteStatus), a.deleteStatus)
+			return
+		}
+		var payload models.Payload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := a.accounts[payload.AccountID]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if a.deleteLingers > 0 {
+			a.lingering[payload.AccountID] = a.deleteLingers
+		} else {
+			delete(a.accounts, payload.AccountID)
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// store saves an account built from payload, as the API does for create and update requests.
+func (a *testAPI) store(payload models.Payload) models.Account {
+	a.lastPayload = payload
+	account := models.Account{
+		OrganizationID:   payload.OrganizationID,
+		AccountID:        payload.AccountID,
+		CloudProvider:    payload.CloudProvider,
+		Region:           payload.Region,
+		StorageClassName: payload.StorageClassName,
+		Products:         payload.Products,
+		Cur:              payload.Cur,
+		Athena:           payload.Athena,
+		SubscriptionID:   payload.SubscriptionID,
+		ProjectID:        payload.ProjectID,
+		Tags:             payload.Tags,
+		UpdatedAt:        a.updatedAt,
+		AdditionalData:   map[string]any{},
+	}
+	for key, value := range payload.AdditionalData {
+		account.AdditionalData[key] = value
+	}
+	account.AdditionalData["roleARN"] = payload.RoleARN
+	account.AdditionalData["externalID"] = payload.ExternalID
+	if a.omitInactive {
+		account.Products = map[models.Product]models.ProductDetails{}
+		for name, details := range payload.Products {
+			if details.Active {
+				account.Products[name] = details
+			}
+		}
+	}
+	a.accounts[payload.AccountID] = account
+	return account
+}
+
+// payloadFromAccount returns the payload an account was stored with.
+func payloadFromAccount(account models.Account) models.Payload {
+	payload := models.Payload{
+		OrganizationID:   account.OrganizationID,
+		AccountID:        account.AccountID,
+		CloudProvider:    account.CloudProvider,
+		Region:           account.Region,
+		StorageClassName: account.StorageClassName,
+		Products:         account.Products,
+		Cur:              account.Cur,
+		Athena:           account.Athena,
+		SubscriptionID:   account.SubscriptionID,
+		ProjectID:        account.ProjectID,
+		Tags:             account.Tags,
+		AdditionalData:   map[string]any{},
+	}
+	for key, value := range account.AdditionalData {
+		switch key {
+		case "roleARN":
+			payload.RoleARN, _ = value.(string)
+		case "externalID":
+			payload.ExternalID, _ = value.(string)
+		default:
+			payload.AdditionalData[key] = value
+		}
+	}
+	return payload
+}
+
+// applyMergePatch applies a JSON merge patch (RFC 7386) to payload.
+func applyMergePatch(payload models.Payload, patch map[string]any) (models.Payload, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return models.Payload{}, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return models.Payload{}, err
+	}
+
+	encoded, err = json.Marshal(mergeObjects(fields, patch))
+	if err != nil {
+		return models.Payload{}, err
+	}
+	var patched models.Payload
+	err = json.Unmarshal(encoded, &patched)
+	return patched, err
+}
+
+func mergeObjects(target map[string]any, patch map[string]any) map[string]any {
+	for key, value := range patch {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		object, isObject := value.(map[string]any)
+		targetObject, targetIsObject := target[key].(map[string]any)
+		if isObject && targetIsObject {
+			target[key] = mergeObjects(targetObject, object)
+			continue
+		}
+		target[key] = value
+	}
+	return target
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func testAccProviderConfig(server *httptest.Server) string {
+	return fmt.Sprintf(`
+provider "zesty" {
+  host  = %q
+  token = "test-token"
+}
+`, server.URL)
+}
+
+func TestAccProvider_TokenNotInState(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTenantsProviderConfig(server) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+
+resource "zesty_account" "tenant" {
+  tenant = "tenant_b"
+  account = {
+    id             = "210987654321"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::210987654321:role/ZestyIamRole"
+    external_id    = "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+
+data "zesty_accounts" "test" {
+  depends_on = [zesty_account.test, zesty_account.tenant]
+}
+`,
+				Check: func(s *terraform.State) error {
+					for name, rs := range s.RootModule().Resources {
+						for key, value := range rs.Primary.Attributes {
+							for _, token := range []string{"test-token", "tenant-b-token", "tenant-c-token"} {
+								if strings.Contains(value, token) {
+									return fmt.Errorf("%s.%s contains the token %q", name, key, token)
+								}
+							}
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_ExternalIDNotInState(t *testing.T) {
+	_, server := newTestAPI(t)
+	externalID := "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + fmt.Sprintf(`
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = %q
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+
+data "zesty_accounts" "test" {
+  depends_on = [zesty_account.test]
+}
+`, externalID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "account.external_id", externalID),
+					resource.TestCheckResourceAttr("data.zesty_accounts.test", "accounts.0.external_id", externalID),
+					func(s *terraform.State) error {
+						for name, rs := range s.RootModule().Resources {
+							for key, value := range rs.Primary.Attributes {
+								if strings.HasSuffix(key, ".external_id") {
+									continue
+								}
+								if strings.Contains(value, externalID) {
+									return fmt.Errorf("%s.%s contains the external ID", name, key)
+								}
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+func TestAccProvider_SkipValidation(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.validateStatus = http.StatusServiceUnavailable
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host            = %q
+  token           = "test-token"
+  skip_validation = true
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				Check: func(_ *terraform.State) error {
+					if api.validateCalls != 0 {
+						return fmt.Errorf("expected /validate not to be called, got %d calls", api.validateCalls)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_RequestTimeout(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.validateDelay = 2 * time.Second
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host            = %q
+  token           = "test-token"
+  request_timeout = 1
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`Client.Timeout exceeded`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_RequestTimeoutInvalid(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host            = %q
+  token           = "test-token"
+  request_timeout = 0
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`must be a positive number of seconds`),
+			},
+		},
+	})
+}
+
+func TestProvider_MetadataVersion(t *testing.T) {
+	resp := &frameworkprovider.MetadataResponse{}
+	provider.New("1.2.3")().Metadata(context.Background(), frameworkprovider.MetadataRequest{}, resp)
+
+	assert.Equal(t, "zesty", resp.TypeName)
+	assert.Equal(t, "1.2.3", resp.Version)
+}
+
+func TestAccProvider_InvalidValuesFormat(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host          = %q
+  token         = "test-token"
+  values_format = "toml"
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`The values format must be either "yaml" or "json"`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_RequestsPerSecondInvalid(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host                = %q
+  token               = "test-token"
+  requests_per_second = 0
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`must be a positive number of requests per second`),
+			},
+		},
+	})
+}
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestAccProvider_Profile(t *testing.T) {
+	api, server := newTestAPI(t)
+	files := map[string]string{
+		"ini": writeCredentialsFile(t, fmt.Sprintf(`
+# Zesty environments
+[default]
+host  = http://127.0.0.1:1
+token = default-token
+
+[dev]
+host  = %s
+token = dev-token
+`, server.URL)),
+		"json": writeCredentialsFile(t, fmt.Sprintf(`{
+  "default": {"host": "http://127.0.0.1:1", "token": "default-token"},
+  "dev": {"host": %q, "token": "dev-token"}
+}`, server.URL)),
+	}
+
+	for format, file := range files {
+		t.Run(format, func(t *testing.T) {
+			api.validateCalls = 0
+
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+provider "zesty" {
+  profile          = "dev"
+  credentials_file = %q
+}
+
+data "zesty_products" "all" {}
+`, file),
+						Check: func(_ *terraform.State) error {
+							if api.validateCalls == 0 {
+								return fmt.Errorf("expected /validate to be called on the profile's host")
+							}
+							return nil
+						},
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccProvider_ProfilePrecedence(t *testing.T) {
+	_, server := newTestAPI(t)
+	t.Setenv("ZESTY_HOST", "http://127.0.0.1:1")
+	t.Setenv("ZESTY_API_TOKEN", "env-token")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The profile overrides the environment variables.
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  profile          = "dev"
+  credentials_file = %q
+}
+
+data "zesty_products" "all" {}
+`, writeCredentialsFile(t, fmt.Sprintf("[dev]\nhost = %s\n", server.URL))),
+			},
+			{
+				// Explicit configuration overrides the profile.
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host             = %q
+  profile          = "dev"
+  credentials_file = %q
+}
+
+data "zesty_products" "all" {}
+`, server.URL, writeCredentialsFile(t, "[dev]\nhost = http://127.0.0.1:1\n")),
+			},
+		},
+	})
+}
+
+func TestAccProvider_ProfileNotFound(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  profile          = "prod"
+  credentials_file = %q
+}
+
+data "zesty_products" "all" {}
+`, writeCredentialsFile(t, "[dev]\ntoken = dev-token\n")),
+				ExpectError: regexp.MustCompile(`Unable to Read Zesty Credentials`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_TokenFile(t *testing.T) {
+	api, server := newTestAPI(t)
+	t.Setenv("ZESTY_API_TOKEN", "env-token")
+	file := writeCredentialsFile(t, "  file-token\n\n")
+
+	expectToken := func(expected string) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if token := api.lastHeader.Get("X-Api-Key"); token != expected {
+				return fmt.Errorf("expected token %q, got %q", expected, token)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The trimmed token file overrides the environment variable.
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host       = %q
+  token_file = %q
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL, file),
+				Check: expectToken("file-token"),
+			},
+			{
+				// An explicit token overrides the token file.
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host       = %q
+  token      = "explicit-token"
+  token_file = %q
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL, file),
+				Check: expectToken("explicit-token"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_TokenFileEnv(t *testing.T) {
+	api, server := newTestAPI(t)
+	t.Setenv("ZESTY_API_TOKEN", "env-token")
+	t.Setenv("ZESTY_API_TOKEN_FILE", writeCredentialsFile(t, "file-token"))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host = %q
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				Check: func(_ *terraform.State) error {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					if token := api.lastHeader.Get("X-Api-Key"); token != "file-token" {
+						return fmt.Errorf("expected token %q, got %q", "file-token", token)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_TokenFileErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing file": filepath.Join(t.TempDir(), "missing"),
+		"empty file":   writeCredentialsFile(t, " \n"),
+	}
+
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+provider "zesty" {
+  host       = "http://127.0.0.1:1"
+  token_file = %q
+}
+
+data "zesty_products" "all" {}
+`, file),
+						ExpectError: regexp.MustCompile(`Unable\s+to\s+Read\s+Zesty\s+Token\s+File`),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccProvider_ValidateUnauthorized(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.validateStatus = http.StatusForbidden
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_products" "all" {}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Zesty API Token`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_APIVersion(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.apiVersion = models.APIVersion
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_products" "all" {}
+`,
+				Check: func(_ *terraform.State) error {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					if version := api.lastHeader.Get(client.APIVersionHeader); version != models.APIVersion {
+						return fmt.Errorf("expected API version header %q, got %q", models.APIVersion, version)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_APIVersionMismatch(t *testing.T) {
+	api, server := newTestAPI(t)
+	api.apiVersion = "v1"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+data "zesty_products" "all" {}
+`,
+				ExpectError: regexp.MustCompile(`Incompatible\s+Zesty\s+API\s+Version`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_BasePath(t *testing.T) {
+	api, _ := newTestAPI(t)
+	gateway := httptest.NewServer(http.StripPrefix("/zesty/v2", api))
+	t.Cleanup(gateway.Close)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host      = %q
+  token     = "test-token"
+  base_path = "zesty/v2/"
+}
+
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+
+data "zesty_accounts" "all" {
+  depends_on = [zesty_account.test]
+}
+`, gateway.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("zesty_account.test", "id", "123456789012"),
+					resource.TestCheckResourceAttr("data.zesty_accounts.all", "account_count", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_AccountIDParam(t *testing.T) {
+	api, _ := newTestAPI(t)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Has("accountID") {
+			http.Error(w, "unexpected accountID parameter", http.StatusBadRequest)
+			return
+		}
+		if query.Has("account_id") {
+			query.Set("accountID", query.Get("account_id"))
+			query.Del("account_id")
+			r.URL.RawQuery = query.Encode()
+		}
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(gateway.Close)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host             = %q
+  token            = "test-token"
+  account_id_param = "account_id"
+}
+
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`, gateway.URL),
+				Check: resource.TestCheckResourceAttr("zesty_account.test", "id", "123456789012"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_Retry(t *testing.T) {
+	api, _ := newTestAPI(t)
+	// conflicts is the number of account listings the gateway still fails with a 409.
+	var conflicts atomic.Int32
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/accounts" && conflicts.Add(-1) >= 0 {
+			http.Error(w, "account listing in progress", http.StatusConflict)
+			return
+		}
+		api.ServeHTTP(w, r)
+	}))
+	t.Cleanup(gateway.Close)
+
+	config := func(retry string) string {
+		return fmt.Sprintf(`
+provider "zesty" {
+  host  = %q
+  token = "test-token"
+%s
+}
+
+data "zesty_accounts" "all" {}
+`, gateway.URL, retry)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { conflicts.Store(1) },
+				Config:      config(""),
+				ExpectError: regexp.MustCompile(`account listing in progress`),
+			},
+			{
+				PreConfig: func() { conflicts.Store(2) },
+				Config: config(`
+  retry {
+    max_attempts    = 3
+    wait_min        = "1ms"
+    wait_max        = "2ms"
+    retry_on_status = [409]
+  }
+`),
+				Check: resource.TestCheckResourceAttr("data.zesty_accounts.all", "accounts.#", "0"),
+			},
+			{
+				PreConfig: func() { conflicts.Store(1) },
+				Config: config(`
+  retry {
+    max_attempts    = 3
+    wait_min        = "1ms"
+    retry_on_status = [503]
+  }
+`),
+				ExpectError: regexp.MustCompile(`account listing in progress`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_RetryInvalid(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	tests := map[string]struct {
+		retry         string
+		expectedError string
+	}{
+		"missing max attempts": {
+			retry:         `wait_min = "1s"`,
+			expectedError: `max_attempts\s+must\s+be\s+set`,
+		},
+		"invalid wait": {
+			retry:         `max_attempts = 3` + "\n" + `wait_min = "soon"`,
+			expectedError: `wait_min\s+must\s+be\s+a\s+non-negative\s+duration`,
+		},
+		"wait max below wait min": {
+			retry:         `max_attempts = 3` + "\n" + `wait_min = "10s"` + "\n" + `wait_max = "1s"`,
+			expectedError: `wait_max\s+1s\s+must\s+not\s+be\s+less\s+than\s+wait_min\s+10s`,
+		},
+		"invalid status": {
+			retry:         `max_attempts = 3` + "\n" + `retry_on_status = [409, 42]`,
+			expectedError: `HTTP\s+status\s+codes\s+between\s+100\s+and\s+599,\s+got\s+42`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+provider "zesty" {
+  host  = %q
+  token = "test-token"
+
+  retry {
+    %s
+  }
+}
+
+data "zesty_products" "all" {}
+`, server.URL, tt.retry),
+						ExpectError: regexp.MustCompile(tt.expectedError),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccProvider_ValidateUnreachable(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "zesty" {
+  host  = "http://127.0.0.1:1"
+  token = "test-token"
+}
+
+data "zesty_products" "all" {}
+`,
+				ExpectError: regexp.MustCompile(`Zesty API Temporarily Unavailable`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_InvalidHost(t *testing.T) {
+	for _, host := range []string{"api.zesty.co", "ftp://api.zesty.co", "https://", "https://api.zesty.co/#junk"} {
+		t.Run(host, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+provider "zesty" {
+  host  = %q
+  token = "test-token"
+}
+
+data "zesty_products" "all" {}
+`, host),
+						ExpectError: regexp.MustCompile(`Invalid Zesty API Host`),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccProvider_ConnectionPool(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host                    = %q
+  token                   = "test-token"
+  max_idle_conns          = 20
+  max_idle_conns_per_host = 20
+  idle_conn_timeout       = 30
+}
+
+data "zesty_products" "all" {}
+`, server.URL),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host           = %q
+  token          = "test-token"
+  max_idle_conns = 0
+}
+
+data "zesty_products" "all" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`Invalid\s+Zesty\s+API\s+Connection\s+Pool\s+Setting`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_ExtraHeaders(t *testing.T) {
+	api, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host  = %q
+  token = "test-token"
+  extra_headers = {
+    "X-Tenant-ID" = "tenant-1"
+    "x-api-key"   = "other-token"
+  }
+}
+`, server.URL) + `
+resource "zesty_account" "test" {
+  account = {
+    id             = "123456789012"
+    cloud_provider = "AWS"
+    role_arn       = "arn:aws:iam::123456789012:role/ZestyIamRole"
+    external_id    = "f1f0a7f7-a523-4197-9e19-ffd205a5bc20"
+    products = [{
+      name   = "Kompass"
+      active = true
+    }]
+  }
+}
+`,
+				Check: func(_ *terraform.State) error {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					if tenant := api.lastHeader.Get("X-Tenant-ID"); tenant != "tenant-1" {
+						return fmt.Errorf("expected X-Tenant-ID header tenant-1, got %q", tenant)
+					}
+					if token := api.lastHeader.Get("X-Api-Key"); token != "test-token" {
+						return fmt.Errorf("expected the reserved X-Api-Key header to keep the provider token, got %q", token)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+// newTestVault serves secrets by path like the Vault HTTP API, requiring the vault-token token.
+func newTestVault(t *testing.T, secrets map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(testVaultHandler(secrets))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testVaultHandler is the handler of newTestVault.
+func testVaultHandler(secrets map[string]any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{"permission denied"}})
+			return
+		}
+		secret, ok := secrets[strings.TrimPrefix(r.URL.Path, "/v1/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": secret})
+	})
+}
+
+func TestAccProvider_Vault(t *testing.T) {
+	api, server := newTestAPI(t)
+	vault := newTestVault(t, map[string]any{
+		// KV version 2 wraps the secret with its metadata.
+		"secret/data/zesty": map[string]any{
+			"data":     map[string]any{"token": "vault-kv2-token", "host": server.URL},
+			"metadata": map[string]any{"version": 1},
+		},
+		"kv/zesty": map[string]any{"token": "vault-kv1-token"},
+	})
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+	t.Setenv("ZESTY_API_TOKEN", "env-token")
+
+	expectToken := func(expected string) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if token := api.lastHeader.Get("X-Api-Key"); token != expected {
+				return fmt.Errorf("expected token %q, got %q", expected, token)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The host and token of a KV version 2 secret override the environment variables.
+				Config: `
+provider "zesty" {
+  vault_token_path = "secret/data/zesty"
+}
+
+data "zesty_accounts" "all" {}
+`,
+				Check: expectToken("vault-kv2-token"),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host             = %q
+  vault_token_path = "kv/zesty"
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				Check: expectToken("vault-kv1-token"),
+			},
+			{
+				// Explicit configuration overrides Vault.
+				Config: `
+provider "zesty" {
+  token            = "config-token"
+  vault_token_path = "secret/data/zesty"
+}
+
+data "zesty_accounts" "all" {}
+`,
+				Check: expectToken("config-token"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_VaultCACert(t *testing.T) {
+	api, server := newTestAPI(t)
+	vault := httptest.NewTLSServer(testVaultHandler(map[string]any{
+		"kv/zesty": map[string]any{"token": "vault-token-over-tls"},
+	}))
+	t.Cleanup(vault.Close)
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: vault.Certificate().Raw}), 0o600))
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host             = %q
+  vault_token_path = "kv/zesty"
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`Unable\s+to\s+Read\s+Zesty\s+Token\s+from\s+Vault`),
+			},
+			{
+				// Vault is reached through the transport of the Zesty API, which trusts ca_cert_file.
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host             = %q
+  vault_token_path = "kv/zesty"
+  ca_cert_file     = %q
+}
+
+data "zesty_accounts" "all" {}
+`, server.URL, caCertFile),
+				Check: func(_ *terraform.State) error {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					if token := api.lastHeader.Get("X-Api-Key"); token != "vault-token-over-tls" {
+						return fmt.Errorf("expected token %q, got %q", "vault-token-over-tls", token)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_VaultErrors(t *testing.T) {
+	vault := newTestVault(t, map[string]any{
+		"secret/data/zesty": map[string]any{
+			"data":     map[string]any{"host": "http://127.0.0.1:1"},
+			"metadata": map[string]any{"version": 1},
+		},
+	})
+	t.Setenv("VAULT_ADDR", vault.URL)
+
+	tests := map[string]struct {
+		vaultToken  string
+		secretPath  string
+		expectError string
+	}{
+		"missing VAULT_TOKEN": {
+			secretPath:  "secret/data/zesty",
+			expectError: `VAULT_TOKEN\s+environment\s+variable\s+is\s+not\s+set`,
+		},
+		"permission denied": {
+			vaultToken:  "other-token",
+			secretPath:  "secret/data/zesty",
+			expectError: `status:\s+403\):\s+permission\s+denied`,
+		},
+		"secret not found": {
+			vaultToken:  "vault-token",
+			secretPath:  "secret/data/missing",
+			expectError: `status:\s+404`,
+		},
+		"secret without token": {
+			vaultToken:  "vault-token",
+			secretPath:  "secret/data/zesty",
+			expectError: `has\s+no\s+token\s+field`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("VAULT_TOKEN", tt.vaultToken)
+
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+provider "zesty" {
+  vault_token_path = %q
+}
+
+data "zesty_products" "all" {}
+`, tt.secretPath),
+						ExpectError: regexp.MustCompile(tt.expectError),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccProvider_MaxTotalRetriesInvalid(t *testing.T) {
+	_, server := newTestAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "zesty" {
+  host              = %q
+  token             = "test-token"
+  max_total_retries = -1
+}
+
+data "zesty_products" "all" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`max_total_retries\s+must\s+not\s+be\s+negative`),
+			},
+		},
+	})
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// vaultTimeout bounds the request reading the Zesty credentials from Vault.
+const vaultTimeout = 30 * time.Second
+
+// vaultResponse is the body of a Vault read request. Data holds the secret for KV version 1 engines,
+// and wraps it with its metadata for KV version 2 engines.
+type vaultResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []string       `json:"errors"`
+}
+
+// readVaultCredentials reads the Zesty host and token from the Vault secret at secretPath with
+// httpClient, using the VAULT_ADDR, VAULT_TOKEN and optional VAULT_NAMESPACE environment variables.
+// The secret must have a token field and may have a host field.
+func readVaultCredentials(ctx context.Context, httpClient *http.Client, secretPath string) (credentialsProfile, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return credentialsProfile{}, fmt.Errorf("the VAULT_ADDR environment variable is not set")
+	}
+	vaultToken := os.Getenv("VAULT_TOKEN")
+	if vaultToken == "" {
+		return credentialsProfile{}, fmt.Errorf("the VAULT_TOKEN environment variable is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, vaultTimeout)
+	defer cancel()
+
+	url := strings.TrimSuffix(addr, "/") + "/v1/" + strings.TrimPrefix(secretPath, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return credentialsProfile{}, err
+	}
+	req.Header.Set("X-Vault-Token", vaultToken)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return credentialsProfile{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return credentialsProfile{}, err
+	}
+
+	var secret vaultResponse
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return credentialsProfile{}, fmt.Errorf("parsing response from %s (status: %d): %w", url, resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(secret.Errors) > 0 {
+			return credentialsProfile{}, fmt.Errorf("reading %s (status: %d): %s", secretPath, resp.StatusCode, strings.Join(secret.Errors, ", "))
+		}
+		return credentialsProfile{}, fmt.Errorf("reading %s (status: %d)", secretPath, resp.StatusCode)
+	}
+
+	return parseVaultSecret(secretPath, secret.Data)
+}
+
+// parseVaultSecret extracts the host and token from the data of a Vault secret, unwrapping the data
+// of KV version 2 secrets.
+func parseVaultSecret(secretPath string, data map[string]any) (credentialsProfile, error) {
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	token, ok := data["token"].(string)
+	if !ok || token == "" {
+		return credentialsProfile{}, fmt.Errorf("secret %s has no token field", secretPath)
+	}
+
+	host, _ := data["host"].(string)
+	return credentialsProfile{Host: host, Token: token}, nil
+}